@@ -0,0 +1,198 @@
+package sss
+
+import (
+	"io"
+)
+
+// AdditiveToShamir converts additive shares of a secret into Shamir shares of
+// the same secret, without the secret ever being assembled. The parts are
+// additive shares: the secret is the XOR of all of them. Returns N Shamir
+// shares of which K are required to recover the secret.
+//
+// This function performs every party's step in one place, which is useful for
+// testing and for single-process use. In a deployment, each party splits its
+// own part with UpgradeAdditiveDeal and sends sub-share X to party X; each
+// party then combines the sub-shares it received with UpgradeAdditiveCollect
+// to form its Shamir share. Because Shamir sharing is linear, the summed
+// shares lie on the sum of the parties' polynomials, whose constant term is
+// the secret.
+//
+// The conversion is only as private as the channels between parties: any
+// party which learns every sub-share destined for K recipients can recover the
+// secret. Each party must also use its own source of randomness; a party which
+// can predict another's polynomial learns that party's part.
+func AdditiveToShamir(parts map[byte][]byte, n, k byte, rand io.Reader) (map[byte][]byte, error) {
+	if len(parts) == 0 {
+		return nil, ErrTooFewShares
+	}
+
+	var l int
+	for _, v := range parts {
+		l = len(v)
+		break
+	}
+
+	holders := SequentialIDs(n)
+
+	received := make(map[byte][][]byte, len(holders))
+	for _, dealer := range ShareSet(parts).IDs() {
+		part := parts[dealer]
+		if len(part) != l {
+			return nil, ErrInvalidLength
+		}
+
+		sub, err := UpgradeAdditiveDeal(part, holders, k, rand)
+		if err != nil {
+			return nil, err
+		}
+
+		for x, y := range sub {
+			received[x] = append(received[x], y)
+		}
+	}
+
+	shares := make(map[byte][]byte, n)
+	for _, x := range holders {
+		y, err := UpgradeAdditiveCollect(received[x])
+		if err != nil {
+			return nil, err
+		}
+		shares[x] = y
+	}
+	return shares, nil
+}
+
+// AddShares adds two sets of shares, which must have the same IDs and
+// lengths, producing shares of the sum of their secrets. Addition in GF(2^8)
+// is XOR, so the new shares recover the XOR of the two secrets.
+//
+// Because Shamir sharing is linear, each party can add its own two shares
+// without communicating with the others, so the sum is computed without any
+// party learning either secret or the result.
+func AddShares(a, b map[byte][]byte) (map[byte][]byte, error) {
+	if len(a) != len(b) {
+		return nil, ErrMismatchedIDs
+	}
+
+	sum := make(map[byte][]byte, len(a))
+	for x, ya := range a {
+		yb, ok := b[x]
+		if !ok {
+			return nil, ErrMismatchedIDs
+		}
+
+		if len(ya) != len(yb) {
+			return nil, ErrInvalidLength
+		}
+
+		y := make([]byte, len(ya))
+		for i := range y {
+			y[i] = ya[i] ^ yb[i]
+		}
+		sum[x] = y
+	}
+	return sum, nil
+}
+
+// MulSharesScalar multiplies every byte of the given shares by the public field
+// element c, producing shares of the secret with each byte multiplied by c in
+// GF(2^8). As with AddShares, each party can scale its own share alone.
+//
+// If c is zero, the resulting shares all recover a secret of zeros, and the
+// original secret cannot be recovered from them; callers which need to undo
+// the multiplication must use a non-zero c.
+func MulSharesScalar(shares map[byte][]byte, c byte) map[byte][]byte {
+	product := make(map[byte][]byte, len(shares))
+	for x, y := range shares {
+		p := make([]byte, len(y))
+		for i, b := range y {
+			p[i] = mul(b, c)
+		}
+		product[x] = p
+	}
+	return product
+}
+
+// UpgradeAdditiveToThreshold converts additive shares of a secret, held by the
+// parties with the given IDs, into Shamir shares of the same secret of which K
+// are required to recover it, without the secret ever being assembled. The
+// parts are additive shares: the secret is the XOR of all of them. Each party
+// keeps its ID, so the returned shares have the same IDs as the parts.
+//
+// This function performs every party's steps in one place, which is useful for
+// testing and for single-process use. In a deployment, each party calls
+// UpgradeAdditiveDeal on its own part, sends sub-share X to party X, and then
+// calls UpgradeAdditiveCollect on the sub-shares it received, including its
+// own, to form its Shamir share.
+//
+// The upgrade has the same trust assumptions as AdditiveToShamir: the channels
+// between parties must be private, and each party must use its own
+// unpredictable source of randomness.
+func UpgradeAdditiveToThreshold(additive map[byte][]byte, k byte, rand io.Reader) (map[byte][]byte, error) {
+	if len(additive) == 0 {
+		return nil, ErrTooFewShares
+	}
+
+	holders := ShareSet(additive).IDs()
+
+	received := make(map[byte][][]byte, len(holders))
+	for _, dealer := range holders {
+		sub, err := UpgradeAdditiveDeal(additive[dealer], holders, k, rand)
+		if err != nil {
+			return nil, err
+		}
+
+		for x, y := range sub {
+			received[x] = append(received[x], y)
+		}
+	}
+
+	shares := make(map[byte][]byte, len(holders))
+	for _, x := range holders {
+		y, err := UpgradeAdditiveCollect(received[x])
+		if err != nil {
+			return nil, err
+		}
+		shares[x] = y
+	}
+	return shares, nil
+}
+
+// UpgradeAdditiveDeal is one party's first step of UpgradeAdditiveToThreshold:
+// it splits the party's additive part into one sub-share for each of the
+// parties with the given IDs, of which K are required to recover the part.
+// Sub-share X must be sent privately to party X.
+func UpgradeAdditiveDeal(part []byte, holders []byte, k byte, rand io.Reader) (map[byte][]byte, error) {
+	if err := Validate(len(holders), int(k), len(part)); err != nil {
+		return nil, err
+	}
+
+	if err := checkIDs(holders); err != nil {
+		return nil, err
+	}
+
+	return splitAt(holders, k, part, rand)
+}
+
+// UpgradeAdditiveCollect is one party's second step of
+// UpgradeAdditiveToThreshold: it sums the sub-shares the party received from
+// every party, including itself, to form its Shamir share of the secret.
+// Returns ErrTooFewShares if there are no sub-shares, and ErrInvalidLength if
+// they differ in length.
+func UpgradeAdditiveCollect(received [][]byte) ([]byte, error) {
+	if len(received) == 0 {
+		return nil, ErrTooFewShares
+	}
+
+	share := make([]byte, len(received[0]))
+	for _, y := range received {
+		if len(y) != len(share) {
+			return nil, ErrInvalidLength
+		}
+
+		for i := range y {
+			share[i] ^= y[i]
+		}
+	}
+	return share, nil
+}
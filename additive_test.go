@@ -0,0 +1,198 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestAdditiveToShamir(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	// two parties hold additive shares of the secret
+	a := make([]byte, len(secret))
+	if _, err := rand.Read(a); err != nil {
+		t.Fatal(err)
+	}
+
+	b := make([]byte, len(secret))
+	for i := range b {
+		b[i] = secret[i] ^ a[i]
+	}
+
+	shares, err := AdditiveToShamir(map[byte][]byte{1: a, 2: b}, 5, 3, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := Combine(subset); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestAdditiveToShamirMismatchedLength(t *testing.T) {
+	parts := map[byte][]byte{1: {1, 2}, 2: {1}}
+
+	if _, err := AdditiveToShamir(parts, 3, 2, rand.Reader); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func TestAddShares(t *testing.T) {
+	s1, s2 := []byte("well hello there!"), []byte("general kenobi!!!")
+
+	a, err := Split(5, 3, s1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Split(5, 3, s2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := AddShares(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(sum).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := make([]byte, len(s1))
+	for i := range want {
+		want[i] = s1[i] ^ s2[i]
+	}
+
+	if v := Combine(subset); !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestAddSharesMismatched(t *testing.T) {
+	for _, c := range []struct {
+		a, b map[byte][]byte
+		err  error
+	}{
+		{map[byte][]byte{1: {1}}, map[byte][]byte{1: {1}, 2: {2}}, ErrMismatchedIDs},
+		{map[byte][]byte{1: {1}}, map[byte][]byte{2: {2}}, ErrMismatchedIDs},
+		{map[byte][]byte{1: {1}}, map[byte][]byte{1: {1, 2}}, ErrInvalidLength},
+	} {
+		if _, err := AddShares(c.a, c.b); err != c.err {
+			t.Errorf("Was %v, but expected %v", err, c.err)
+		}
+	}
+}
+
+func TestMulSharesScalar(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []byte{0, 1, 2, 0x53, 0xff} {
+		subset, err := ShareSet(MulSharesScalar(shares, c)).Subset(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := make([]byte, len(secret))
+		for i, b := range secret {
+			want[i] = mul(b, c)
+		}
+
+		if v := Combine(subset); !bytes.Equal(v, want) {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+	}
+}
+
+func TestUpgradeAdditiveToThreshold(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	// three parties, with scattered IDs, hold additive shares of the secret
+	additive := map[byte][]byte{3: make([]byte, len(secret)), 9: make([]byte, len(secret))}
+	for _, part := range additive {
+		if _, err := rand.Read(part); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	last := append([]byte(nil), secret...)
+	for _, part := range additive {
+		for i := range last {
+			last[i] ^= part[i]
+		}
+	}
+	additive[200] = last
+
+	shares, err := UpgradeAdditiveToThreshold(additive, 2, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := ShareSet(shares).IDs(), []byte{3, 9, 200}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	for _, ids := range [][]byte{{3, 9}, {3, 200}, {9, 200}} {
+		v, err := CombineUsing(shares, ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(v, secret) {
+			t.Errorf("IDs %v recovered %v, but expected %v", ids, v, secret)
+		}
+	}
+}
+
+func TestUpgradeAdditiveSteps(t *testing.T) {
+	holders := []byte{1, 2, 3}
+	parts := [][]byte{{1, 2}, {3, 4}, {5, 6}}
+
+	// each party deals its part, and each sub-share is delivered
+	received := make(map[byte][][]byte)
+	for _, part := range parts {
+		sub, err := UpgradeAdditiveDeal(part, holders, 3, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for x, y := range sub {
+			received[x] = append(received[x], y)
+		}
+	}
+
+	shares := make(map[byte][]byte)
+	for _, x := range holders {
+		y, err := UpgradeAdditiveCollect(received[x])
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares[x] = y
+	}
+
+	if v, want := Combine(shares), []byte{1 ^ 3 ^ 5, 2 ^ 4 ^ 6}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestUpgradeAdditiveInvalid(t *testing.T) {
+	if _, err := UpgradeAdditiveDeal([]byte{1}, []byte{1, 1}, 2, rand.Reader); err != ErrDuplicateID {
+		t.Errorf("Was %v, but expected %v", err, ErrDuplicateID)
+	}
+
+	if _, err := UpgradeAdditiveCollect([][]byte{{1}, {1, 2}}); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
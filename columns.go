@@ -0,0 +1,51 @@
+package sss
+
+// ShareColumn returns the IDs of the given shares, in ascending order, and the
+// byte of each share at the given index, in the same order, for storing shares
+// by byte position rather than by party. Returns nil slices if the index is
+// negative or beyond the end of any share.
+func ShareColumn(shares map[byte][]byte, byteIndex int) (ids, values []byte) {
+	if byteIndex < 0 {
+		return nil, nil
+	}
+
+	for _, y := range shares {
+		if byteIndex >= len(y) {
+			return nil, nil
+		}
+	}
+
+	ids = ShareSet(shares).IDs()
+	values = make([]byte, len(ids))
+	for j, x := range ids {
+		values[j] = shares[x][byteIndex]
+	}
+	return ids, values
+}
+
+// SharesFromColumns assembles shares from columns as returned by ShareColumn,
+// where columns[i][j] is the byte at index i of the share with ID ids[j].
+// Returns ErrInvalidID if any ID is zero, ErrDuplicateID if any ID appears
+// more than once, and ErrInvalidLength if any column does not hold one byte
+// for each ID.
+func SharesFromColumns(ids []byte, columns [][]byte) (map[byte][]byte, error) {
+	if err := checkIDs(ids); err != nil {
+		return nil, err
+	}
+
+	shares := make(map[byte][]byte, len(ids))
+	for _, x := range ids {
+		shares[x] = make([]byte, len(columns))
+	}
+
+	for i, column := range columns {
+		if len(column) != len(ids) {
+			return nil, ErrInvalidLength
+		}
+
+		for j, x := range ids {
+			shares[x][i] = column[j]
+		}
+	}
+	return shares, nil
+}
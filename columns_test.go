@@ -0,0 +1,66 @@
+package sss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestShareColumnRoundtrip(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []byte
+	columns := make([][]byte, len(secret))
+	for i := range columns {
+		ids, columns[i] = ShareColumn(shares, i)
+	}
+
+	if v, want := ids, []byte{1, 2, 3, 4, 5}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	rebuilt, err := SharesFromColumns(ids, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for x, y := range shares {
+		if v := rebuilt[x]; !bytes.Equal(v, y) {
+			t.Errorf("Share %v was %v, but expected %v", x, v, y)
+		}
+	}
+
+	if v := Combine(rebuilt); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestShareColumnOutOfRange(t *testing.T) {
+	shares := map[byte][]byte{1: {1, 2}, 2: {3}}
+
+	for _, i := range []int{-1, 1, 2} {
+		if ids, values := ShareColumn(shares, i); ids != nil || values != nil {
+			t.Errorf("Index %v was %v and %v, but expected nil", i, ids, values)
+		}
+	}
+}
+
+func TestSharesFromColumnsInvalid(t *testing.T) {
+	for _, c := range []struct {
+		ids     []byte
+		columns [][]byte
+		err     error
+	}{
+		{[]byte{0, 1}, [][]byte{{1, 2}}, ErrInvalidID},
+		{[]byte{1, 1}, [][]byte{{1, 2}}, ErrDuplicateID},
+		{[]byte{1, 2}, [][]byte{{1, 2}, {3}}, ErrInvalidLength},
+	} {
+		if _, err := SharesFromColumns(c.ids, c.columns); err != c.err {
+			t.Errorf("Was %v, but expected %v", err, c.err)
+		}
+	}
+}
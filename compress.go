@@ -0,0 +1,112 @@
+package sss
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// the flag recorded before an uncompressed secret
+	secretStored = 0
+	// the flag recorded before a flate-compressed secret
+	secretCompressed = 1
+	// the flag byte and the secret's original length
+	compressedHeaderSize = 1 + 4
+)
+
+// SplitCompressed splits the given secret into N shares of which K are
+// required to recover the secret, as with Split, but first compresses the
+// secret with flate, so that compressible secrets produce smaller shares. If
+// compression would not make the secret smaller, it is stored as is.
+//
+// The split value is a flag byte recording whether the secret was compressed,
+// the secret's original length as a big-endian uint32, and the compressed or
+// stored secret. Use CombineCompressed to recover the secret.
+//
+// N.B.: The length of the shares reveals how well the secret compresses, which
+// may reveal something about its contents.
+func SplitCompressed(n, k byte, secret []byte) (map[byte][]byte, error) {
+	if uint64(len(secret)) > math.MaxUint32 {
+		return nil, ErrInvalidLength
+	}
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, compressedHeaderSize))
+
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(secret); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	value := buf.Bytes()
+	value[0] = secretCompressed
+	if len(value) >= compressedHeaderSize+len(secret) {
+		value = append(value[:compressedHeaderSize], secret...)
+		value[0] = secretStored
+	}
+	binary.BigEndian.PutUint32(value[1:], uint32(len(secret)))
+
+	shares, err := Split(n, k, value)
+
+	// don't leave a copy of the secret lying around
+	for i := range value {
+		value[i] = 0
+	}
+
+	return shares, err
+}
+
+// CombineCompressed combines shares split with SplitCompressed into the
+// original secret, decompressing it if necessary. Returns an error wrapping
+// ErrIntegrity if the recovered value is not a valid compressed or stored
+// secret, which happens if fewer than K shares, or a corrupted share, were
+// combined, and otherwise validates the shares as CombineChecked does.
+//
+// N.B.: The decompression checks are consistency checks only. They do not
+// prevent a malicious party from altering the recovered secret.
+func CombineCompressed(shares map[byte][]byte) ([]byte, error) {
+	value, err := CombineChecked(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) < compressedHeaderSize {
+		return nil, ErrIntegrity
+	}
+
+	l := int64(binary.BigEndian.Uint32(value[1:]))
+	body := value[compressedHeaderSize:]
+
+	switch value[0] {
+	case secretStored:
+		if int64(len(body)) != l {
+			return nil, ErrIntegrity
+		}
+		return body, nil
+	case secretCompressed:
+		// read one byte past the recorded length to detect a longer secret
+		secret, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(body)), l+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrIntegrity, err)
+		}
+
+		if int64(len(secret)) != l {
+			return nil, ErrIntegrity
+		}
+		return secret, nil
+	default:
+		return nil, ErrIntegrity
+	}
+}
@@ -0,0 +1,69 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSplitCompressed(t *testing.T) {
+	random := make([]byte, 1000)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name       string
+		secret     []byte
+		compressed bool
+	}{
+		{"compressible", bytes.Repeat([]byte(`{"well": "hello there!"}`), 40), true},
+		{"incompressible", random, false},
+		{"empty", nil, false},
+	} {
+		shares, err := SplitCompressed(5, 3, c.secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		l := len(shares[1])
+		if c.compressed && l >= len(c.secret) {
+			t.Errorf("%s: share was %v bytes, but expected fewer than %v", c.name, l, len(c.secret))
+		}
+
+		if !c.compressed && l != compressedHeaderSize+len(c.secret) {
+			t.Errorf("%s: share was %v bytes, but expected %v", c.name, l, compressedHeaderSize+len(c.secret))
+		}
+
+		subset, err := ShareSet(shares).Subset(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v, err := CombineCompressed(subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(v, c.secret) {
+			t.Errorf("%s: didn't recover the secret", c.name)
+		}
+	}
+}
+
+func TestCombineCompressedTooFewShares(t *testing.T) {
+	shares, err := SplitCompressed(5, 3, bytes.Repeat([]byte("well hello there!"), 40))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CombineCompressed(subset); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("Was %v, but expected %v", err, ErrIntegrity)
+	}
+}
@@ -0,0 +1,78 @@
+package sss
+
+import (
+	"crypto/rand"
+)
+
+// SplitN splits the given secret into N shares of which K are required to
+// recover the secret, as with Split, but returns the shares as a slice of
+// length N+1 in which element X is the share with ID X. Element 0 is nil.
+func SplitN(n, k byte, secret []byte) ([][]byte, error) {
+	if err := Validate(int(n), int(k), len(secret)); err != nil {
+		return nil, err
+	}
+
+	shares := make([][]byte, int(n)+1)
+	buf := make([]byte, int(n)*len(secret))
+	for x := 1; x <= int(n); x++ {
+		shares[x] = buf[(x-1)*len(secret) : x*len(secret) : x*len(secret)]
+	}
+
+	for i, b := range secret {
+		p, err := generate(k-1, b, rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		for x := 1; x <= int(n); x++ {
+			shares[x][i] = eval(p, byte(x))
+		}
+	}
+
+	return shares, nil
+}
+
+// CombineN combines shares laid out as returned by SplitN, in which element X
+// is the share with ID X, into the original secret. Nil elements are skipped,
+// as is element 0. Returns ErrTooFewShares if fewer than K shares are present
+// and ErrInvalidLength if they differ in length.
+//
+// N.B.: There is no way to know whether the returned value is, in fact, the
+// original secret.
+func CombineN(shares [][]byte, k byte) ([]byte, error) {
+	if err := checkThreshold(k); err != nil {
+		return nil, err
+	}
+
+	if len(shares) > 256 {
+		return nil, ErrInvalidID
+	}
+
+	var points []pair
+	l := -1
+	for x := 1; x < len(shares); x++ {
+		if shares[x] == nil {
+			continue
+		}
+
+		if l >= 0 && len(shares[x]) != l {
+			return nil, ErrInvalidLength
+		}
+		l = len(shares[x])
+		points = append(points, pair{x: byte(x)})
+	}
+
+	if len(points) < int(k) {
+		return nil, ErrTooFewShares
+	}
+
+	secret := make([]byte, l)
+	for i := range secret {
+		for j := range points {
+			points[j].y = shares[points[j].x][i]
+		}
+		secret[i] = interpolate(points, 0)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,125 @@
+package sss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitN(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := SplitN(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := len(shares), 6; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if shares[0] != nil {
+		t.Errorf("Was %v, but expected nil", shares[0])
+	}
+
+	shares[2], shares[4] = nil, nil
+
+	actual, err := CombineN(shares, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Errorf("Was %v, but expected %v", actual, secret)
+	}
+}
+
+func TestSplitNMatchesSplit(t *testing.T) {
+	dense, err := SplitN(5, 3, []byte("well hello there!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shares := make(map[byte][]byte)
+	for x := 1; x < len(dense); x += 2 {
+		shares[byte(x)] = dense[x]
+	}
+
+	if v, want := Combine(shares), []byte("well hello there!"); !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestCombineNTooFewShares(t *testing.T) {
+	shares := [][]byte{nil, {1}, nil, {3}}
+
+	if _, err := CombineN(shares, 3); err != ErrTooFewShares {
+		t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+	}
+}
+
+func TestCombineNMismatchedLength(t *testing.T) {
+	shares := [][]byte{nil, {1}, {2, 3}}
+
+	if _, err := CombineN(shares, 2); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func BenchmarkSplit(b *testing.B) {
+	secret := make([]byte, 1024)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(secret)))
+	for i := 0; i < b.N; i++ {
+		if _, err := Split(10, 3, secret); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSplitN(b *testing.B) {
+	secret := make([]byte, 1024)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(secret)))
+	for i := 0; i < b.N; i++ {
+		if _, err := SplitN(10, 3, secret); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCombine(b *testing.B) {
+	shares, err := Split(10, 3, make([]byte, 1024))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Combine(subset)
+	}
+}
+
+func BenchmarkCombineN(b *testing.B) {
+	shares, err := SplitN(10, 3, make([]byte, 1024))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	subset := shares[:4]
+
+	b.ReportAllocs()
+	b.SetBytes(1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := CombineN(subset, 3); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package sss
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"fmt"
+	"io"
+)
+
+// the version of the key derivation used by SplitDeterministic
+const deterministicVersion = 1
+
+// SplitDeterministic splits the given secret into N shares of which K are
+// required to recover the secret, as with Split, but derives the polynomials'
+// coefficients from the secret itself, so that splitting the same secret with
+// the same parameters and domain separator always produces the same shares.
+// This allows shares to be written idempotently or deduplicated. The domain
+// separator, which must not be empty, should identify the application and
+// purpose, so that unrelated systems splitting the same secret produce
+// unrelated shares.
+//
+// A key is derived from the secret with HKDF-SHA256, using as the info a
+// version byte, N, K, and the domain separator, and the coefficients are read
+// from AES-256-CTR keyed with it. Splits of the same secret with different N
+// or K therefore use unrelated polynomials, and shares of one cannot be pooled
+// with shares of another to reach a threshold.
+//
+// N.B.: This is weaker than Split. Anyone holding a single share, and knowing
+// the domain separator, can check a guess of the whole secret by splitting it
+// and comparing the result, so the secret must be unguessable, such as a
+// random key. Splitting the same secret twice also reveals that the secrets
+// are equal. Re-splitting a secret with the same parameters, such as to
+// replace shares which may have leaked, reproduces the same shares, so it
+// requires a new domain separator.
+func SplitDeterministic(n, k byte, secret []byte, domainSep []byte) (map[byte][]byte, error) {
+	if len(domainSep) == 0 {
+		return nil, fmt.Errorf("%w: domain separator must not be empty", ErrInvalidLength)
+	}
+
+	rand, err := deterministicReader(n, k, secret, domainSep)
+	if err != nil {
+		return nil, err
+	}
+	return split(n, k, secret, rand)
+}
+
+// returns the stream of coefficients for a deterministic split
+func deterministicReader(n, k byte, secret []byte, domainSep []byte) (io.Reader, error) {
+	info := append([]byte{deterministicVersion, n, k}, domainSep...)
+	key, err := hkdf.Key(sha256.New, secret, nil, string(info), 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	return cipher.StreamReader{S: cipher.NewCTR(block, iv), R: zeroReader{}}, nil
+}
+
+// reads an endless stream of zeros
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
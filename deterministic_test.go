@@ -0,0 +1,96 @@
+package sss
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSplitDeterministic(t *testing.T) {
+	secret := []byte("well hello there!")
+	domain := []byte("sss test")
+
+	a, err := SplitDeterministic(5, 3, secret, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := SplitDeterministic(5, 3, secret, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !SharesEqual(a, b) {
+		t.Errorf("Was %v, but expected %v", b, a)
+	}
+
+	subset, err := ShareSet(a).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := Combine(subset); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestSplitDeterministicDiffers(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	a, err := SplitDeterministic(5, 3, secret, []byte("sss test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		secret, domain []byte
+	}{
+		{[]byte("well hello there?"), []byte("sss test")},
+		{secret, []byte("another test")},
+	} {
+		b, err := SplitDeterministic(5, 3, c.secret, c.domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the secrets have the same first byte, so the shares of it differ
+		// only if the coefficients do
+		if a[1][0] == b[1][0] && a[2][0] == b[2][0] && a[3][0] == b[3][0] {
+			t.Errorf("Shares of %q under %q matched the original", c.secret, c.domain)
+		}
+	}
+}
+
+func TestSplitDeterministicParamsBound(t *testing.T) {
+	secret := []byte("well hello there!")
+	domain := []byte("sss test")
+
+	coefficients := func(n, k byte) []byte {
+		r, err := deterministicReader(n, k, secret, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b := make([]byte, 64)
+		if _, err := io.ReadFull(r, b); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	// splits with different parameters which shared coefficients could be
+	// pooled to recover the secret from fewer shares than either threshold
+	a := coefficients(5, 2)
+	for _, c := range []struct{ n, k byte }{{5, 3}, {6, 2}} {
+		if v := coefficients(c.n, c.k); bytes.Equal(v, a) {
+			t.Errorf("%v-of-%v coefficients matched those of 2-of-5", c.k, c.n)
+		}
+	}
+}
+
+func TestSplitDeterministicNoDomain(t *testing.T) {
+	if _, err := SplitDeterministic(5, 3, []byte("secret"), nil); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
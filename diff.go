@@ -0,0 +1,58 @@
+package sss
+
+import (
+	"bytes"
+)
+
+// ShareDiff describes how a share differs between two sets of shares.
+type ShareDiff struct {
+	ID         byte  // the ID of the share
+	InA, InB   bool  // whether the share is present in each set
+	LenA, LenB int   // the share's length in each set, or zero if absent
+	Offsets    []int // the offsets, within both shares, of differing bytes
+}
+
+// SharesEqual reports whether the two sets of shares have the same IDs and
+// identical shares.
+//
+// N.B.: The comparison does not take constant time, so it is intended for
+// debugging rather than for checking shares against secret values.
+func SharesEqual(a, b map[byte][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for x, y := range a {
+		if z, ok := b[x]; !ok || !bytes.Equal(y, z) {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffShares compares two sets of shares, returning a ShareDiff for each share
+// which is absent from either set or differs between them, in order of ID.
+// Returns nil if the sets are equal.
+func DiffShares(a, b map[byte][]byte) []ShareDiff {
+	var diffs []ShareDiff
+	// ID 0 is not a valid share, but a corrupt set may still hold one
+	for x := 0; x < fieldSize; x++ {
+		y, inA := a[byte(x)]
+		z, inB := b[byte(x)]
+		if !inA && !inB {
+			continue
+		}
+
+		d := ShareDiff{ID: byte(x), InA: inA, InB: inB, LenA: len(y), LenB: len(z)}
+		for i := 0; i < min(len(y), len(z)); i++ {
+			if y[i] != z[i] {
+				d.Offsets = append(d.Offsets, i)
+			}
+		}
+
+		if inA != inB || len(y) != len(z) || len(d.Offsets) > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
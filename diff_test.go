@@ -0,0 +1,46 @@
+package sss
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSharesEqual(t *testing.T) {
+	a := map[byte][]byte{1: {1, 2, 3}, 2: {4, 5, 6}}
+
+	for _, c := range []struct {
+		b    map[byte][]byte
+		want bool
+	}{
+		{map[byte][]byte{1: {1, 2, 3}, 2: {4, 5, 6}}, true},
+		{map[byte][]byte{1: {1, 2, 3}}, false},
+		{map[byte][]byte{1: {1, 2, 3}, 3: {4, 5, 6}}, false},
+		{map[byte][]byte{1: {1, 2, 3}, 2: {4, 0, 6}}, false},
+		{map[byte][]byte{1: {1, 2, 3}, 2: {4, 5}}, false},
+	} {
+		if v := SharesEqual(a, c.b); v != c.want {
+			t.Errorf("Was %v, but expected %v", v, c.want)
+		}
+	}
+}
+
+func TestDiffShares(t *testing.T) {
+	a := map[byte][]byte{1: {1, 2, 3}, 2: {4, 5, 6}, 3: {7, 8, 9}, 4: {1}}
+	b := map[byte][]byte{0: {6}, 1: {1, 2, 3}, 2: {4, 0, 0}, 4: {1, 2}, 5: {3}}
+
+	want := []ShareDiff{
+		{ID: 0, InB: true, LenB: 1},
+		{ID: 2, InA: true, InB: true, LenA: 3, LenB: 3, Offsets: []int{1, 2}},
+		{ID: 3, InA: true, LenA: 3},
+		{ID: 4, InA: true, InB: true, LenA: 1, LenB: 2},
+		{ID: 5, InB: true, LenB: 1},
+	}
+
+	if v := DiffShares(a, b); fmt.Sprint(v) != fmt.Sprint(want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if v := DiffShares(a, a); v != nil {
+		t.Errorf("Was %v, but expected nil", v)
+	}
+}
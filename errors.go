@@ -0,0 +1,96 @@
+package sss
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors originating in this package are, or wrap, one of the following
+// sentinel errors, a *ShareError, or a *RowError, and can be identified with
+// errors.Is and errors.As.
+var (
+	// ErrInvalidCount is returned when the count parameter is invalid.
+	ErrInvalidCount = errors.New("N must be >= K")
+	// ErrInvalidThreshold is returned when the threshold parameter is invalid.
+	ErrInvalidThreshold = errors.New("K must be > 1")
+	// ErrZeroThreshold is returned when the threshold parameter is zero. It
+	// wraps ErrInvalidThreshold.
+	ErrZeroThreshold = fmt.Errorf("%w: K of 0 is meaningless", ErrInvalidThreshold)
+	// ErrThresholdOfOne is returned when the threshold parameter is one. Every
+	// share of a secret split with K=1 is the secret itself, so such a split
+	// provides no confidentiality. It wraps ErrInvalidThreshold.
+	ErrThresholdOfOne = fmt.Errorf("%w: K of 1 makes every share a copy of the secret",
+		ErrInvalidThreshold)
+	// ErrInvalidID is returned when a share ID is zero.
+	ErrInvalidID = errors.New("share IDs must be > 0")
+	// ErrDuplicateID is returned when a share ID appears more than once.
+	ErrDuplicateID = errors.New("share IDs must be distinct")
+	// ErrInvalidLength is returned when shares are of differing lengths, or a
+	// length is out of range.
+	ErrInvalidLength = errors.New("shares must be the same length")
+	// ErrMismatchedIDs is returned when the number of IDs and shares differ.
+	ErrMismatchedIDs = errors.New("must have one ID per share")
+	// ErrConflictingShares is returned when two shares with the same ID have
+	// different values.
+	ErrConflictingShares = errors.New("shares with the same ID differ")
+	// ErrTooFewShares is returned when fewer than K shares are available.
+	ErrTooFewShares = errors.New("must have at least K shares")
+	// ErrNilWriter is returned when a nil writer is given for a share.
+	ErrNilWriter = errors.New("share writers must not be nil")
+	// ErrDegenerateRandomness is returned when a source of randomness yields
+	// nothing but zeros for a polynomial's leading coefficient, as only a
+	// broken source would.
+	ErrDegenerateRandomness = errors.New("source of randomness yields only zeros")
+	// ErrIntegrity is returned when recovered data fails its integrity check,
+	// either because too few shares were combined or because a share was
+	// corrupted.
+	ErrIntegrity = errors.New("recovered data failed integrity check")
+	// ErrTooManyBlocks is returned when too many blocks of a multiplexed share
+	// stream are awaiting their shares or the blocks before them.
+	ErrTooManyBlocks = errors.New("too many blocks in flight")
+	// ErrLabelTooLong is returned when a share's label cannot be encoded.
+	ErrLabelTooLong = errors.New("label must be < 64KiB")
+	// ErrMalformedShare is returned when an encoded share cannot be parsed.
+	ErrMalformedShare = errors.New("malformed share")
+	// ErrCorruptShare is returned when an encoded share fails its checksum.
+	ErrCorruptShare = errors.New("share checksum mismatch")
+	// ErrAuthentication is returned when an encrypted share cannot be
+	// decrypted, because the passphrase is wrong or the share was altered.
+	ErrAuthentication = errors.New("share decryption failed")
+	// ErrUnsupportedVersion is returned when an encoded share is of a newer
+	// version than this package understands.
+	ErrUnsupportedVersion = errors.New("unsupported share version")
+	// ErrShareTooLarge is returned when an encoded share would exceed a size
+	// limit.
+	ErrShareTooLarge = errors.New("encoded share exceeds size limit")
+	// ErrMixedShares is returned when shares from different splits are
+	// combined.
+	ErrMixedShares = errors.New("shares are from different splits")
+)
+
+// ShareError is returned when a particular share cannot be decoded.
+type ShareError struct {
+	ID     byte   // the ID of the failing share
+	Reason string // a description of the failure
+	Err    error  // the underlying error, if any
+}
+
+func (e *ShareError) Error() string {
+	return fmt.Sprintf("share %d: %s", e.ID, e.Reason)
+}
+
+// Unwrap returns the underlying error.
+func (e *ShareError) Unwrap() error {
+	return e.Err
+}
+
+// returns an error describing why the given threshold is invalid, if it is
+func checkThreshold(k byte) error {
+	switch k {
+	case 0:
+		return ErrZeroThreshold
+	case 1:
+		return ErrThresholdOfOne
+	}
+	return nil
+}
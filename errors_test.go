@@ -0,0 +1,132 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestErrorsIs(t *testing.T) {
+	corrupt, _ := (&Share{ID: 1, Threshold: 2}).MarshalBinary()
+	corrupt[0] ^= 1
+
+	for name, c := range map[string]struct {
+		fn   func() error
+		want error
+	}{
+		"invalid count": {func() error {
+			_, err := Split(2, 3, nil)
+			return err
+		}, ErrInvalidCount},
+		"invalid threshold": {func() error {
+			_, err := Split(2, 1, nil)
+			return err
+		}, ErrInvalidThreshold},
+		"degenerate randomness": {func() error {
+			_, err := SplitWithReader(3, 2, []byte("hi"), bytes.NewReader(make([]byte, 1024)))
+			return err
+		}, ErrDegenerateRandomness},
+		"invalid ID": {func() error {
+			_, err := CombineIndexed([]byte{0}, [][]byte{nil})
+			return err
+		}, ErrInvalidID},
+		"duplicate ID": {func() error {
+			_, err := CombineIndexed([]byte{1, 1}, [][]byte{nil, nil})
+			return err
+		}, ErrDuplicateID},
+		"invalid length": {func() error {
+			_, err := CombineFromMatrix(strings.NewReader("1,2\n2\n"))
+			return err
+		}, ErrInvalidLength},
+		"mismatched IDs": {func() error {
+			_, err := CombineIndexed([]byte{1}, nil)
+			return err
+		}, ErrMismatchedIDs},
+		"too few shares": {func() error {
+			_, err := AdditiveToShamir(nil, 2, 2, rand.Reader)
+			return err
+		}, ErrTooFewShares},
+		"integrity": {func() error {
+			return OpenStream(map[byte]io.Reader{1: bytes.NewReader([]byte{0, 0, 0, 1, 0})}, io.Discard)
+		}, ErrIntegrity},
+		"too many blocks": {func() error {
+			buf := new(bytes.Buffer)
+			for i := 0; i <= muxMaxInFlight; i++ {
+				_ = WriteMuxFrame(buf, uint64(i), 1, []byte{1})
+			}
+			return CombineMux(buf, 2, io.Discard)
+		}, ErrTooManyBlocks},
+		"label too long": {func() error {
+			_, err := SplitWithMeta(2, 2, nil, Meta{Label: strings.Repeat("a", 1<<16)})
+			return err
+		}, ErrLabelTooLong},
+		"malformed share": {func() error {
+			_, err := CombineFromMatrix(strings.NewReader("1,x\n"))
+			return err
+		}, ErrMalformedShare},
+		"corrupt share": {func() error {
+			return new(Share).UnmarshalBinary(corrupt)
+		}, ErrCorruptShare},
+		"authentication": {func() error {
+			b, err := EncryptShare(1, nil, "")
+			if err != nil {
+				return err
+			}
+			b[len(b)-1] ^= 1
+
+			_, _, err = DecryptShare(b, "")
+			return err
+		}, ErrAuthentication},
+		"unsupported version": {func() error {
+			return new(Share).UnmarshalBinary([]byte{shareVersion + 1})
+		}, ErrUnsupportedVersion},
+		"mixed shares": {func() error {
+			_, err := CombineShares([]Share{{Threshold: 2}, {Threshold: 3}})
+			return err
+		}, ErrMixedShares},
+		"share error": {func() error {
+			return OpenStream(map[byte]io.Reader{1: bytes.NewReader(nil)}, io.Discard)
+		}, io.ErrUnexpectedEOF},
+		"random source": {func() error {
+			_, _, err := SplitWithVeto(2, 2, []byte{1}, bytes.NewReader(nil))
+			return err
+		}, io.EOF},
+	} {
+		if err := c.fn(); !errors.Is(err, c.want) {
+			t.Errorf("%s: was %v, but expected %v", name, err, c.want)
+		}
+	}
+}
+
+func TestShareErrorAs(t *testing.T) {
+	err := OpenStream(map[byte]io.Reader{7: bytes.NewReader(nil)}, io.Discard)
+
+	var e *ShareError
+	if !errors.As(err, &e) {
+		t.Fatalf("Was %v, but expected a ShareError", err)
+	}
+
+	if v, want := e.ID, byte(7); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestThresholdErrors(t *testing.T) {
+	for k, want := range map[byte]error{0: ErrZeroThreshold, 1: ErrThresholdOfOne} {
+		_, err := Split(3, k, []byte("secret"))
+		if err != want {
+			t.Errorf("K=%v: was %v, but expected %v", k, err, want)
+		}
+
+		if !errors.Is(err, ErrInvalidThreshold) {
+			t.Errorf("K=%v: was %v, but expected %v", k, err, ErrInvalidThreshold)
+		}
+	}
+
+	if ErrZeroThreshold.Error() == ErrThresholdOfOne.Error() {
+		t.Error("Threshold errors should be distinct")
+	}
+}
@@ -0,0 +1,42 @@
+package sss
+
+// EstimateSplitCost returns the number of field operations Split performs to
+// split a secret of the given length into N shares of which K are required.
+//
+// Each byte of the secret is encoded as a polynomial of degree K-1, which is
+// evaluated at N points with K multiplications apiece.
+func EstimateSplitCost(n, k byte, secretLen int) int {
+	return int(n) * int(k) * secretLen
+}
+
+// EstimateCombineCost returns the number of field operations Combine performs
+// to recover a secret of the given length from K shares.
+//
+// Each byte of the secret is recovered by Lagrange interpolation, which
+// computes a weight for each of the K points with K-1 multiplications and K-1
+// divisions, then multiplies it by that point's value. With exactly two
+// shares, the two weights are the same for every byte, so they are computed
+// once and each byte costs only two multiplications.
+func EstimateCombineCost(k byte, secretLen int) int {
+	if k == 2 {
+		return 2 + 2*secretLen
+	}
+	return int(k) * (2*int(k) - 1) * secretLen
+}
+
+// DetectableErrors returns the number of corrupt shares which can always be
+// detected among the given total number of shares of a secret split with
+// threshold K, such as by QuickVerify: any K honest shares determine the
+// secret's polynomials, so up to total-K corrupt shares cannot all agree with
+// them. Returns zero if there are no more than K shares.
+func DetectableErrors(total, k int) int {
+	return max(total-k, 0)
+}
+
+// CorrectableErrors returns the number of corrupt shares which can be
+// corrected, by a decoder which finds the polynomials agreeing with the most
+// shares, among the given total number of shares of a secret split with
+// threshold K. This is half the number of detectable errors, rounded down.
+func CorrectableErrors(total, k int) int {
+	return DetectableErrors(total, k) / 2
+}
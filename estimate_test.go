@@ -0,0 +1,67 @@
+package sss
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestEstimateSplitCost(t *testing.T) {
+	if v, want := EstimateSplitCost(5, 3, 100), 1500; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestEstimateCombineCost(t *testing.T) {
+	if v, want := EstimateCombineCost(3, 100), 1500; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestEstimateCombineCostPair(t *testing.T) {
+	if v, want := EstimateCombineCost(2, 100), 202; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestDetectableErrors(t *testing.T) {
+	for _, c := range []struct {
+		total, k, detectable, correctable int
+	}{
+		{7, 3, 4, 2},
+		{5, 3, 2, 1},
+		{3, 3, 0, 0},
+		{2, 3, 0, 0},
+	} {
+		if v, want := DetectableErrors(c.total, c.k), c.detectable; v != want {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+
+		if v, want := CorrectableErrors(c.total, c.k), c.correctable; v != want {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+	}
+}
+
+func TestDetectableErrorsMatchesQuickVerify(t *testing.T) {
+	const total, k = 7, 3
+
+	secret := []byte("well hello there!")
+	for corrupt := 1; corrupt <= DetectableErrors(total, k); corrupt++ {
+		shares, err := Split(total, k, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// corrupt every byte of the lowest-ID shares, which QuickVerify uses
+		// to reconstruct the polynomials
+		for x := 1; x <= corrupt; x++ {
+			for i := range shares[byte(x)] {
+				shares[byte(x)][i] ^= 0x5a
+			}
+		}
+
+		if ok, err := QuickVerify(shares, k, 4, rand.Reader); ok || err != nil {
+			t.Errorf("%v corrupt shares were not detected", corrupt)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package sss
+
+import (
+	"io"
+	"os"
+)
+
+// CombineLazyFiles combines shares stored one per file, such as those written
+// by SplitChanInput, and writes the recovered secret to dst. paths maps each
+// share ID to the file holding that share.
+//
+// The files are read in lockstep, a block at a time, so memory use is bounded
+// regardless of the secret's length. Each file is opened only while a block is
+// read from it, so at most one file is open at a time, however many shares are
+// combined. Returns a *ShareError wrapping ErrInvalidLength if a file differs
+// in length from the others, or a *ShareError if a file cannot be read.
+//
+// Blocks are written to dst as they are recovered, so on error dst may have
+// received a prefix of the secret.
+func CombineLazyFiles(paths map[byte]string, dst io.Writer) error {
+	if len(paths) == 0 {
+		return ErrTooFewShares
+	}
+
+	for id := range paths {
+		if id == 0 {
+			return ErrInvalidID
+		}
+	}
+
+	bufs := make(map[byte][]byte, len(paths))
+	for id := range paths {
+		bufs[id] = make([]byte, streamBlockSize)
+	}
+
+	shares := make(map[byte][]byte, len(paths))
+	for offset := int64(0); ; offset += streamBlockSize {
+		for id, path := range paths {
+			n, err := readFileBlock(path, offset, bufs[id])
+			if err != nil {
+				return &ShareError{ID: id, Reason: err.Error(), Err: err}
+			}
+			shares[id] = bufs[id][:n]
+		}
+
+		l := commonLength(shares)
+		for _, id := range ShareSet(shares).IDs() {
+			if len(shares[id]) != l {
+				return &ShareError{
+					ID:     id,
+					Reason: "file differs in length from those of the other shares",
+					Err:    ErrInvalidLength,
+				}
+			}
+		}
+
+		if l > 0 {
+			if _, err := dst.Write(Combine(shares)); err != nil {
+				return err
+			}
+		}
+
+		if l < streamBlockSize {
+			return nil
+		}
+	}
+}
+
+// read the block of the named file at the given offset, opening the file only
+// for as long as it takes
+func readFileBlock(path string, offset int64, buf []byte) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	n, err := f.ReadAt(buf, offset)
+	if err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+// SplitRegion splits the region of src of the given length starting at the
+// given offset, such as part of a large file, into N shares of which K are
+// required to recover it, writing the share with ID i+1 to out[i]. Only the
+// requested region is read, a block at a time, so memory use is bounded
+// regardless of its length.
+//
+// Returns io.ErrUnexpectedEOF if src ends before the end of the region, or any
+// other error encountered reading from src. On error, out may have received a
+// prefix of each share.
+func SplitRegion(src io.ReaderAt, offset, length int64, n, k byte, out []io.Writer) error {
+	if err := checkWriters(n, out); err != nil {
+		return err
+	}
+
+	// each block's length is validated as it is split
+	if err := Validate(int(n), int(k), 0); err != nil {
+		return err
+	}
+
+	if offset < 0 || length < 0 {
+		return ErrInvalidLength
+	}
+
+	r := io.NewSectionReader(src, offset, length)
+	buf := make([]byte, streamBlockSize)
+	for remaining := length; remaining > 0; {
+		chunk := buf[:min(remaining, int64(len(buf)))]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		remaining -= int64(len(chunk))
+
+		shares, err := Split(n, k, chunk)
+		if err != nil {
+			return err
+		}
+
+		for i, w := range out {
+			if _, err := w.Write(shares[byte(i+1)]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
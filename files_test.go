@@ -0,0 +1,153 @@
+package sss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writes each share to its own file, returning a map of IDs to paths
+func writeShareFiles(t *testing.T, shares map[byte][]byte) map[byte]string {
+	dir := t.TempDir()
+
+	paths := make(map[byte]string, len(shares))
+	for id, y := range shares {
+		path := filepath.Join(dir, fmt.Sprintf("share-%d", id))
+		if err := os.WriteFile(path, y, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		paths[id] = path
+	}
+	return paths
+}
+
+func TestCombineLazyFiles(t *testing.T) {
+	secret := make([]byte, 2*streamBlockSize+100)
+	for i := range secret {
+		secret[i] = byte(i * 7)
+	}
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dst bytes.Buffer
+	if err := CombineLazyFiles(writeShareFiles(t, subset), &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := dst.Bytes(); !bytes.Equal(v, secret) {
+		t.Errorf("Recovered %d bytes, but expected the %d-byte secret", len(v), len(secret))
+	}
+}
+
+func TestCombineLazyFilesTruncated(t *testing.T) {
+	shares, err := Split(5, 3, make([]byte, streamBlockSize+100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := ShareSet(subset).IDs()
+	subset[ids[1]] = subset[ids[1]][:streamBlockSize+50]
+
+	var dst bytes.Buffer
+	err = CombineLazyFiles(writeShareFiles(t, subset), &dst)
+
+	var se *ShareError
+	if !errors.As(err, &se) || se.ID != ids[1] {
+		t.Errorf("Was %v, but expected a *ShareError for share %v", err, ids[1])
+	}
+
+	if !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func TestCombineLazyFilesMissing(t *testing.T) {
+	paths := map[byte]string{1: filepath.Join(t.TempDir(), "missing")}
+
+	var se *ShareError
+	if err := CombineLazyFiles(paths, &bytes.Buffer{}); !errors.As(err, &se) || se.ID != 1 {
+		t.Errorf("Was %v, but expected a *ShareError for share 1", err)
+	}
+}
+
+// writes the given contents to a temporary file, returning it open for reading
+func tempFile(t *testing.T, contents []byte) *os.File {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, contents, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+func TestSplitRegion(t *testing.T) {
+	contents := make([]byte, 3*streamBlockSize)
+	for i := range contents {
+		contents[i] = byte(i * 7)
+	}
+	f := tempFile(t, contents)
+
+	offset, length := int64(1000), int64(streamBlockSize+500)
+
+	bufs := make([]*bytes.Buffer, 4)
+	out := make([]io.Writer, 4)
+	for i := range bufs {
+		bufs[i] = new(bytes.Buffer)
+		out[i] = bufs[i]
+	}
+
+	if err := SplitRegion(f, offset, length, 4, 3, out); err != nil {
+		t.Fatal(err)
+	}
+
+	shares := map[byte][]byte{
+		1: bufs[0].Bytes(),
+		3: bufs[2].Bytes(),
+		4: bufs[3].Bytes(),
+	}
+
+	want := contents[offset : offset+length]
+	if v := Combine(shares); !bytes.Equal(v, want) {
+		t.Errorf("Recovered %d bytes, but expected the %d-byte region", len(v), len(want))
+	}
+}
+
+func TestSplitRegionPastEnd(t *testing.T) {
+	f := tempFile(t, make([]byte, 100))
+
+	out := []io.Writer{io.Discard, io.Discard, io.Discard}
+	if err := SplitRegion(f, 50, 100, 3, 2, out); err != io.ErrUnexpectedEOF {
+		t.Errorf("Was %v, but expected %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestSplitRegionNegative(t *testing.T) {
+	f := tempFile(t, make([]byte, 100))
+
+	out := []io.Writer{io.Discard, io.Discard, io.Discard}
+	if err := SplitRegion(f, -1, 10, 3, 2, out); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
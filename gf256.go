@@ -1,6 +1,7 @@
 package sss
 
 func mul(e, a byte) byte {
+	countMul()
 	if e == 0 || a == 0 {
 		return 0
 	}
@@ -8,6 +9,7 @@ func mul(e, a byte) byte {
 }
 
 func div(e, a byte) byte {
+	countDiv()
 	if a == 0 {
 		panic("div by zero")
 	}
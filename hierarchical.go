@@ -0,0 +1,74 @@
+package sss
+
+import (
+	"math"
+)
+
+// GroupSpec describes one group of a hierarchical split, such as the parties
+// at a single site: the group's share is split into N shares of which K are
+// required to recover it.
+type GroupSpec struct {
+	N, K byte
+}
+
+// SplitHierarchical splits the given secret between groups of parties, of
+// which T groups are required to recover the secret. The secret is first split
+// into one share per group, of which T are required, and each group's share is
+// then split between that group's parties as described by its GroupSpec.
+// Returns the parties' shares for each group, in the same order as groups.
+//
+// The secret can be recovered, with CombineHierarchical, once any T groups
+// each have at least K of their own parties' shares. Shares from a group which
+// does not reach its threshold contribute nothing, and shares cannot be pooled
+// across groups.
+func SplitHierarchical(groups []GroupSpec, t byte, secret []byte) ([]map[byte][]byte, error) {
+	if len(groups) > math.MaxUint8 {
+		return nil, ErrInvalidCount
+	}
+
+	groupShares, err := Split(byte(len(groups)), t, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]map[byte][]byte, len(groups))
+	for i, g := range groups {
+		if shares[i], err = Split(g.N, g.K, groupShares[byte(i+1)]); err != nil {
+			return nil, err
+		}
+	}
+	return shares, nil
+}
+
+// CombineHierarchical combines shares split with SplitHierarchical into the
+// original secret. groups and t must be those the secret was split with, and
+// shares[i] holds the available shares of the parties in groups[i], and may be
+// empty. Returns ErrTooFewShares if fewer than T groups have at least K of
+// their parties' shares.
+//
+// N.B.: As with Combine, there is no way to know whether the returned value
+// is, in fact, the original secret.
+func CombineHierarchical(groups []GroupSpec, t byte, shares []map[byte][]byte) ([]byte, error) {
+	if len(shares) != len(groups) || len(groups) > math.MaxUint8 {
+		return nil, ErrInvalidCount
+	}
+
+	groupShares := make(map[byte][]byte, len(groups))
+	for i, g := range groups {
+		if len(shares[i]) == 0 || len(shares[i]) < int(g.K) {
+			continue
+		}
+
+		y, err := CombineChecked(shares[i])
+		if err != nil {
+			return nil, err
+		}
+		groupShares[byte(i+1)] = y
+	}
+
+	if len(groupShares) == 0 || len(groupShares) < int(t) {
+		return nil, ErrTooFewShares
+	}
+
+	return CombineChecked(groupShares)
+}
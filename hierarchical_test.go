@@ -0,0 +1,65 @@
+package sss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitHierarchical(t *testing.T) {
+	secret := []byte("well hello there!")
+	groups := []GroupSpec{{N: 3, K: 2}, {N: 5, K: 3}, {N: 2, K: 2}}
+
+	shares, err := SplitHierarchical(groups, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, g := range groups {
+		if v, want := len(shares[i]), int(g.N); v != want {
+			t.Errorf("Group %v was %v shares, but expected %v", i, v, want)
+		}
+	}
+
+	// returns the first k shares of each group, in the given amounts
+	available := func(ks ...int) []map[byte][]byte {
+		sub := make([]map[byte][]byte, len(ks))
+		for i, k := range ks {
+			sub[i] = make(map[byte][]byte, k)
+			for x := 1; x <= k; x++ {
+				sub[i][byte(x)] = shares[i][byte(x)]
+			}
+		}
+		return sub
+	}
+
+	for _, c := range []struct {
+		counts []int
+		err    error
+	}{
+		{[]int{2, 3, 0}, nil},
+		{[]int{0, 3, 2}, nil},
+		{[]int{3, 5, 2}, nil},
+		{[]int{2, 0, 2}, nil},
+		{[]int{2, 2, 1}, ErrTooFewShares},
+		{[]int{1, 5, 0}, ErrTooFewShares},
+		{[]int{0, 0, 0}, ErrTooFewShares},
+	} {
+		v, err := CombineHierarchical(groups, 2, available(c.counts...))
+		if err != c.err {
+			t.Errorf("%v: was %v, but expected %v", c.counts, err, c.err)
+			continue
+		}
+
+		if err == nil && !bytes.Equal(v, secret) {
+			t.Errorf("%v: was %v, but expected %v", c.counts, v, secret)
+		}
+	}
+}
+
+func TestCombineHierarchicalMismatchedGroups(t *testing.T) {
+	groups := []GroupSpec{{N: 3, K: 2}, {N: 3, K: 2}}
+
+	if _, err := CombineHierarchical(groups, 2, nil); err != ErrInvalidCount {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidCount)
+	}
+}
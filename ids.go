@@ -0,0 +1,135 @@
+package sss
+
+import (
+	"crypto/rand"
+)
+
+// ShareID is the ID of a share, between 1 and 255. A ShareID can only be
+// constructed with NewShareID, which validates it; the zero ShareID is not a
+// valid ID, and is rejected wherever one is used.
+type ShareID struct {
+	x byte
+}
+
+// NewShareID returns the given byte as a ShareID, or ErrInvalidID if it is
+// zero.
+func NewShareID(b byte) (ShareID, error) {
+	if b == 0 {
+		return ShareID{}, ErrInvalidID
+	}
+	return ShareID{x: b}, nil
+}
+
+// Byte returns the ID as a byte, as used by the map-based functions.
+func (id ShareID) Byte() byte {
+	return id.x
+}
+
+// SplitByShareID splits the given secret into shares with the given IDs, of
+// which K are required to recover the secret, as with SplitWithIDs.
+func SplitByShareID(k byte, ids []ShareID, secret []byte) (map[ShareID][]byte, error) {
+	raw := make([]byte, len(ids))
+	for i, id := range ids {
+		raw[i] = id.x
+	}
+
+	shares, err := SplitWithIDs(k, raw, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	typed := make(map[ShareID][]byte, len(shares))
+	for x, y := range shares {
+		typed[ShareID{x: x}] = y
+	}
+	return typed, nil
+}
+
+// CombineByShareID combines the given shares into the original secret, as
+// with CombineChecked. Returns ErrInvalidID if any ID is the zero ShareID.
+//
+// N.B.: There is no way to know whether the returned value is, in fact, the
+// original secret.
+func CombineByShareID(shares map[ShareID][]byte) ([]byte, error) {
+	raw := make(map[byte][]byte, len(shares))
+	for id, y := range shares {
+		raw[id.x] = y
+	}
+	return CombineChecked(raw)
+}
+
+// IDStrategy returns the IDs to assign to N shares. The IDs must be distinct
+// and non-zero.
+type IDStrategy func(n byte) []byte
+
+// SequentialIDs is the default IDStrategy, which assigns the IDs 1 through N.
+func SequentialIDs(n byte) []byte {
+	ids := make([]byte, n)
+	for i := range ids {
+		ids[i] = byte(i + 1)
+	}
+	return ids
+}
+
+// AvailableIDs returns, in ascending order, every valid share ID (1-255) which
+// is not already used by the given shares, such as for choosing the ID of a
+// share issued with RegenerateShare.
+func AvailableIDs(shares map[byte][]byte) []byte {
+	ids := make([]byte, 0, fieldSize-1)
+	for x := 1; x < fieldSize; x++ {
+		if _, ok := shares[byte(x)]; !ok {
+			ids = append(ids, byte(x))
+		}
+	}
+	return ids
+}
+
+// SplitWithIDs splits the given secret into shares with the given IDs, of
+// which K are required to recover the secret. Returns ErrInvalidID if any ID
+// is zero, ErrDuplicateID if any ID appears more than once, and
+// ErrInvalidCount if there are fewer than K IDs.
+func SplitWithIDs(k byte, ids []byte, secret []byte) (map[byte][]byte, error) {
+	if err := Validate(len(ids), int(k), len(secret)); err != nil {
+		return nil, err
+	}
+
+	if err := checkIDs(ids); err != nil {
+		return nil, err
+	}
+
+	return splitAt(ids, k, secret, rand.Reader)
+}
+
+// returns an error if any of the IDs is zero or appears more than once
+func checkIDs(ids []byte) error {
+	var seen [fieldSize]bool
+	for _, x := range ids {
+		if x == 0 {
+			return ErrInvalidID
+		}
+
+		if seen[x] {
+			return ErrDuplicateID
+		}
+		seen[x] = true
+	}
+	return nil
+}
+
+// SplitWithStrategy splits the given secret into N shares of which K are
+// required to recover the secret, as with Split, but assigns share IDs using
+// the given strategy. If strategy is nil, SequentialIDs is used. Returns
+// ErrInvalidCount if the strategy does not return N IDs, and otherwise
+// validates the IDs as SplitWithIDs does.
+func SplitWithStrategy(n, k byte, secret []byte, strategy IDStrategy) (map[byte][]byte, error) {
+	if strategy == nil {
+		strategy = SequentialIDs
+	}
+
+	ids := strategy(n)
+	if len(ids) != int(n) {
+		return nil, ErrInvalidCount
+	}
+
+	return SplitWithIDs(k, ids, secret)
+}
@@ -0,0 +1,160 @@
+package sss
+
+import (
+	"bytes"
+	"testing"
+)
+
+// spreads IDs across the field using every seventh power of its generator
+func spreadIDs(n byte) []byte {
+	ids := make([]byte, n)
+	for i := range ids {
+		ids[i] = exp[i*7%255]
+	}
+	return ids
+}
+
+func TestSequentialIDs(t *testing.T) {
+	if v, want := SequentialIDs(4), []byte{1, 2, 3, 4}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestAvailableIDs(t *testing.T) {
+	shares := map[byte][]byte{1: nil, 3: nil, 128: nil, 254: nil}
+
+	ids := AvailableIDs(shares)
+	if v, want := len(ids), 251; v != want {
+		t.Fatalf("Was %v, but expected %v", v, want)
+	}
+
+	if v, want := ids[:3], []byte{2, 4, 5}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if v, want := ids[len(ids)-2:], []byte{253, 255}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	for i, x := range ids {
+		if _, ok := shares[x]; ok {
+			t.Errorf("ID %v is in use", x)
+		}
+
+		if i > 0 && x <= ids[i-1] {
+			t.Errorf("Was %v, but expected an ID after %v", x, ids[i-1])
+		}
+	}
+}
+
+func TestSplitWithStrategy(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := SplitWithStrategy(5, 3, secret, spreadIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := ShareSet(shares).IDs(), []byte{1, 19, 30, 115, 255}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	subset, err := ShareSet(shares).RandomSubset(3, bytes.NewReader([]byte{4, 1, 0}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := Combine(subset); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestSplitWithStrategyDefault(t *testing.T) {
+	shares, err := SplitWithStrategy(3, 2, []byte("secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := ShareSet(shares).IDs(), []byte{1, 2, 3}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestSplitWithStrategyErrors(t *testing.T) {
+	for _, c := range []struct {
+		strategy IDStrategy
+		err      error
+	}{
+		{func(n byte) []byte { return []byte{1, 2} }, ErrInvalidCount},
+		{func(n byte) []byte { return []byte{1, 0, 3} }, ErrInvalidID},
+		{func(n byte) []byte { return []byte{1, 3, 3} }, ErrDuplicateID},
+	} {
+		if _, err := SplitWithStrategy(3, 2, []byte("secret"), c.strategy); err != c.err {
+			t.Errorf("Was %v, but expected %v", err, c.err)
+		}
+	}
+}
+
+func TestSplitWithIDsTooFew(t *testing.T) {
+	if _, err := SplitWithIDs(3, []byte{1, 2}, []byte("secret")); err != ErrInvalidCount {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidCount)
+	}
+}
+
+func TestNewShareID(t *testing.T) {
+	if _, err := NewShareID(0); err != ErrInvalidID {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidID)
+	}
+
+	if v, err := NewShareID(255); err != nil || v.Byte() != 255 {
+		t.Errorf("Was %v (%v), but expected %v", v.Byte(), err, 255)
+	}
+}
+
+func TestSplitByShareID(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	var ids []ShareID
+	for _, b := range []byte{7, 42, 200} {
+		id, err := NewShareID(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	shares, err := SplitByShareID(2, ids, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delete(shares, ids[1])
+
+	v, err := CombineByShareID(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestShareIDInvalid(t *testing.T) {
+	one, err := NewShareID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SplitByShareID(2, []ShareID{{}, one}, []byte("hi")); err != ErrInvalidID {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidID)
+	}
+
+	if _, err := SplitByShareID(2, []ShareID{one, one}, []byte("hi")); err != ErrDuplicateID {
+		t.Errorf("Was %v, but expected %v", err, ErrDuplicateID)
+	}
+
+	if _, err := CombineByShareID(map[ShareID][]byte{{}: {1}, one: {2}}); err != ErrInvalidID {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidID)
+	}
+}
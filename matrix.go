@@ -0,0 +1,112 @@
+package sss
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CombineFromMatrix reads shares laid out as a comma-separated matrix from r
+// and combines them into the original secret.
+//
+// Each row of the matrix is a share: the first column is the share ID (1-255),
+// and each remaining column is a byte of the share, in order. IDs and bytes
+// are written in decimal (e.g. 171) or in hexadecimal with a 0x prefix (e.g.
+// 0xab); leading zeros do not denote octal. Whitespace before a value is
+// ignored. Every row must have the same number of columns. Returns
+// ErrTooFewShares if the matrix has no rows.
+func CombineFromMatrix(r io.Reader) ([]byte, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrTooFewShares
+	}
+
+	ids := make([]byte, len(rows))
+	payloads := make([][]byte, len(rows))
+	for i, row := range rows {
+		if len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("%w: row %d has %d columns, expected %d",
+				ErrInvalidLength, i+1, len(row), len(rows[0]))
+		}
+
+		values := make([]byte, len(row))
+		for j, field := range row {
+			v, err := parseMatrixByte(field)
+			if err != nil {
+				return nil, fmt.Errorf("%w: row %d, column %d: %q is not a byte",
+					ErrMalformedShare, i+1, j+1, field)
+			}
+			values[j] = v
+		}
+
+		ids[i], payloads[i] = values[0], values[1:]
+	}
+
+	return CombineIndexed(ids, payloads)
+}
+
+// parse a decimal or 0x-prefixed hexadecimal byte
+func parseMatrixByte(s string) (byte, error) {
+	s = strings.TrimSpace(s)
+
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s, base = s[2:], 16
+	}
+
+	v, err := strconv.ParseUint(s, base, 8)
+	return byte(v), err
+}
+
+// SplitMatrix splits the given secret into N shares of which K are required to
+// recover the secret, returning them as a single row-major matrix of N rows
+// and len(secret) columns, in which row i is the share with ID i+1, along with
+// the matrix's dimensions. This suits writing the shares as a raw binary dump
+// for numeric tools, without framing each share.
+func SplitMatrix(n, k byte, secret []byte) (matrix []byte, rows, cols int, err error) {
+	shares, err := SplitN(n, k, secret)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	rows, cols = int(n), len(secret)
+	matrix = make([]byte, 0, rows*cols)
+	for x := 1; x <= rows; x++ {
+		matrix = append(matrix, shares[x]...)
+	}
+	return matrix, rows, cols, nil
+}
+
+// CombineMatrix combines shares laid out as a row-major matrix of the given
+// dimensions, as returned by SplitMatrix, into the original secret, where row
+// i is the share with ID ids[i]. Returns ErrInvalidLength if the matrix does
+// not have the given dimensions, ErrMismatchedIDs if there is not one ID per
+// row, and otherwise validates the IDs as CombineIndexed does.
+//
+// N.B.: There is no way to know whether the returned value is, in fact, the
+// original secret.
+func CombineMatrix(matrix []byte, rows, cols int, ids []byte) ([]byte, error) {
+	if rows < 0 || cols < 0 || (cols > 0 && rows > len(matrix)/cols) || rows*cols != len(matrix) {
+		return nil, ErrInvalidLength
+	}
+
+	if len(ids) != rows {
+		return nil, ErrMismatchedIDs
+	}
+
+	payloads := make([][]byte, rows)
+	for i := range payloads {
+		payloads[i] = matrix[i*cols : (i+1)*cols]
+	}
+	return CombineIndexed(ids, payloads)
+}
@@ -0,0 +1,135 @@
+package sss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCombineFromMatrix(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	for _, id := range []byte{1, 3, 4} {
+		fmt.Fprintf(buf, "%d", id)
+		for i, b := range shares[id] {
+			if i%2 == 0 {
+				fmt.Fprintf(buf, ", %d", b)
+			} else {
+				fmt.Fprintf(buf, ", 0x%02x", b)
+			}
+		}
+		fmt.Fprintln(buf)
+	}
+
+	actual, err := CombineFromMatrix(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Errorf("Was %v, but expected %v", actual, secret)
+	}
+}
+
+func TestCombineFromMatrixRagged(t *testing.T) {
+	m := "1,2,3\n2,4\n"
+
+	if _, err := CombineFromMatrix(strings.NewReader(m)); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func TestCombineFromMatrixBadByte(t *testing.T) {
+	m := "1,2,3\n2,4,256\n"
+
+	if _, err := CombineFromMatrix(strings.NewReader(m)); !errors.Is(err, ErrMalformedShare) {
+		t.Errorf("Was %v, but expected %v", err, ErrMalformedShare)
+	}
+}
+
+func TestCombineFromMatrixEmpty(t *testing.T) {
+	for _, m := range []string{"", "\n\n"} {
+		if _, err := CombineFromMatrix(strings.NewReader(m)); err != ErrTooFewShares {
+			t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+		}
+	}
+}
+
+func TestCombineFromMatrixZeroID(t *testing.T) {
+	m := "0,2,3\n2,4,5\n"
+
+	if _, err := CombineFromMatrix(strings.NewReader(m)); err != ErrInvalidID {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidID)
+	}
+}
+
+func TestParseMatrixByte(t *testing.T) {
+	for s, want := range map[string]byte{"17": 17, "017": 17, "0x11": 17, "0XfF": 255} {
+		if v, err := parseMatrixByte(s); err != nil || v != want {
+			t.Errorf("%q was %v (%v), but expected %v", s, v, err, want)
+		}
+	}
+}
+
+func TestSplitMatrix(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	matrix, rows, cols, err := SplitMatrix(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rows != 5 || cols != len(secret) || len(matrix) != rows*cols {
+		t.Fatalf("Was %vx%v (%v bytes), but expected 5x%v", rows, cols, len(matrix), len(secret))
+	}
+
+	// rows 1, 3, and 4 hold the shares with IDs 2, 4, and 5
+	sub := append(append(append([]byte(nil), matrix[cols:2*cols]...),
+		matrix[3*cols:4*cols]...), matrix[4*cols:]...)
+
+	v, err := CombineMatrix(sub, 3, cols, []byte{2, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+
+	v, err = CombineMatrix(matrix, rows, cols, []byte{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestCombineMatrixInvalid(t *testing.T) {
+	matrix := make([]byte, 6)
+
+	for _, c := range []struct {
+		rows, cols int
+		ids        []byte
+		err        error
+	}{
+		{2, 4, []byte{1, 2}, ErrInvalidLength},
+		{-2, -3, []byte{1, 2}, ErrInvalidLength},
+		{2, 3, []byte{1}, ErrMismatchedIDs},
+		{2, 3, []byte{0, 1}, ErrInvalidID},
+		{2, 3, []byte{1, 1}, ErrDuplicateID},
+	} {
+		if _, err := CombineMatrix(matrix, c.rows, c.cols, c.ids); err != c.err {
+			t.Errorf("%vx%v: was %v, but expected %v", c.rows, c.cols, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package sss
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+const (
+	// the largest payload accepted in a multiplexed frame
+	muxMaxPayload = streamBlockSize
+	// the most blocks CombineMux buffers while awaiting shares or earlier
+	// blocks
+	muxMaxInFlight = 16
+)
+
+// WriteMuxFrame writes a single frame of a multiplexed share stream, as read by
+// CombineMux.
+//
+// A frame consists of the block's byte offset in the secret as a big-endian
+// uint64, the share ID, the payload's length as a big-endian uint32, and the
+// payload, which is share ID's bytes for that block.
+func WriteMuxFrame(w io.Writer, offset uint64, id byte, payload []byte) error {
+	if len(payload) > muxMaxPayload {
+		return ErrInvalidLength
+	}
+
+	h := make([]byte, 0, 13)
+	h = binary.BigEndian.AppendUint64(h, offset)
+	h = append(h, id)
+	h = binary.BigEndian.AppendUint32(h, uint32(len(payload)))
+	if _, err := w.Write(h); err != nil {
+		return err
+	}
+
+	_, err := w.Write(payload)
+	return err
+}
+
+// CombineMux reads frames of a multiplexed share stream from r, as written by
+// WriteMuxFrame, and writes the recovered secret to dst. Frames may arrive in
+// any order; once K frames for a block have arrived the block is recovered,
+// and any further frames for it are discarded.
+//
+// Blocks are written to dst in order of offset. Only blocks which have not yet
+// been written are buffered, and at most 16 such blocks are buffered at once,
+// so memory use is bounded by K times the block size for each block in
+// flight, not by the size of the secret. Writers must therefore keep the
+// frames of nearby blocks together in the stream.
+//
+// Returns ErrTooManyBlocks if more blocks would be buffered, and
+// ErrTooFewShares if the stream ends before every block has been recovered.
+func CombineMux(r io.Reader, k byte, dst io.Writer) error {
+	if err := checkThreshold(k); err != nil {
+		return err
+	}
+
+	var next uint64
+	pending := make(map[uint64]map[byte][]byte)
+	recovered := make(map[uint64][]byte)
+
+	h := make([]byte, 13)
+	for {
+		if _, err := io.ReadFull(r, h); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		offset, id := binary.BigEndian.Uint64(h), h[8]
+		l := binary.BigEndian.Uint32(h[9:])
+		if id == 0 {
+			return ErrInvalidID
+		}
+
+		if l > muxMaxPayload {
+			return ErrInvalidLength
+		}
+
+		payload := make([]byte, l)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		if _, ok := recovered[offset]; ok || offset < next {
+			continue // already recovered
+		}
+
+		shares := pending[offset]
+		if shares == nil {
+			if len(pending)+len(recovered) >= muxMaxInFlight {
+				return ErrTooManyBlocks
+			}
+
+			shares = make(map[byte][]byte, k)
+			pending[offset] = shares
+		}
+
+		for _, y := range shares {
+			if len(y) != len(payload) {
+				return ErrInvalidLength
+			}
+			break
+		}
+
+		if _, ok := shares[id]; ok {
+			return ErrDuplicateID
+		}
+		shares[id] = payload
+
+		if len(shares) < int(k) {
+			continue
+		}
+
+		delete(pending, offset)
+		recovered[offset] = Combine(shares)
+
+		for block, ok := recovered[next]; ok; block, ok = recovered[next] {
+			if _, err := dst.Write(block); err != nil {
+				return err
+			}
+			delete(recovered, next)
+			next += uint64(len(block))
+		}
+	}
+
+	if len(pending) > 0 || len(recovered) > 0 {
+		return ErrTooFewShares
+	}
+
+	return nil
+}
+
+// SplitInterleaved splits the given secret into N shares of which K are
+// required to recover the secret, as with Split, and returns them all in a
+// single byte stream for transports which offer only one. The stream is a
+// sequence of N frames in order of share ID, each consisting of the share ID,
+// the share's length as a big-endian uint32, and the share.
+func SplitInterleaved(n, k byte, secret []byte) ([]byte, error) {
+	shares, err := Split(n, k, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 0, int(n)*(5+len(secret)))
+	for x := 1; x <= int(n); x++ {
+		b = append(b, byte(x))
+		b = binary.BigEndian.AppendUint32(b, uint32(len(shares[byte(x)])))
+		b = append(b, shares[byte(x)]...)
+	}
+	return b, nil
+}
+
+// DeinterleaveShares parses a byte stream of frames, as returned by
+// SplitInterleaved, into a map of share IDs to shares. Returns
+// io.ErrUnexpectedEOF if the stream ends partway through a frame.
+func DeinterleaveShares(b []byte) (map[byte][]byte, error) {
+	shares := make(map[byte][]byte)
+	for len(b) > 0 {
+		if len(b) < 5 {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		id, l := b[0], binary.BigEndian.Uint32(b[1:])
+		b = b[5:]
+		if uint64(l) > uint64(len(b)) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		if id == 0 {
+			return nil, ErrInvalidID
+		}
+
+		if _, ok := shares[id]; ok {
+			return nil, ErrDuplicateID
+		}
+
+		shares[id] = b[:l:l]
+		b = b[l:]
+	}
+	return shares, nil
+}
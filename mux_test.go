@@ -0,0 +1,169 @@
+package sss
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestCombineMux(t *testing.T) {
+	secret := []byte("well hello there, how are you?")
+	blocks := [][]byte{secret[:10], secret[10:20], secret[20:]}
+
+	type frame struct {
+		offset uint64
+		id     byte
+		y      []byte
+	}
+
+	var frames []frame
+	for i, b := range blocks {
+		shares, err := Split(4, 2, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for id, y := range shares {
+			frames = append(frames, frame{uint64(i * 10), id, y})
+		}
+	}
+
+	// interleave the frames out of order: last block first, then the rest
+	// with each block's frames split across the stream
+	buf := new(bytes.Buffer)
+	order := append(frames[8:], frames[:8]...)
+	for i := 0; i < len(order); i += 2 {
+		f := order[i]
+		if err := WriteMuxFrame(buf, f.offset, f.id, f.y); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i < len(order); i += 2 {
+		f := order[i]
+		if err := WriteMuxFrame(buf, f.offset, f.id, f.y); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := new(bytes.Buffer)
+	if err := CombineMux(buf, 2, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(out.Bytes(), secret) {
+		t.Errorf("Was %q, but expected %q", out.Bytes(), secret)
+	}
+}
+
+func TestCombineMuxTooFewShares(t *testing.T) {
+	shares, err := Split(3, 3, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	for _, id := range []byte{1, 2} {
+		if err := WriteMuxFrame(buf, 0, id, shares[id]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := CombineMux(buf, 3, new(bytes.Buffer)); err != ErrTooFewShares {
+		t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+	}
+}
+
+func TestCombineMuxTooManyBlocks(t *testing.T) {
+	// one share of each block, so none can be recovered
+	buf := new(bytes.Buffer)
+	for i := 0; i <= muxMaxInFlight; i++ {
+		if err := WriteMuxFrame(buf, uint64(i*2), 1, []byte{1, 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := CombineMux(buf, 2, new(bytes.Buffer)); err != ErrTooManyBlocks {
+		t.Errorf("Was %v, but expected %v", err, ErrTooManyBlocks)
+	}
+}
+
+func TestCombineMuxDuplicateID(t *testing.T) {
+	buf := new(bytes.Buffer)
+	for i := 0; i < 2; i++ {
+		if err := WriteMuxFrame(buf, 0, 1, []byte{1, 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := CombineMux(buf, 3, new(bytes.Buffer)); err != ErrDuplicateID {
+		t.Errorf("Was %v, but expected %v", err, ErrDuplicateID)
+	}
+}
+
+func TestCombineMuxMismatchedLength(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteMuxFrame(buf, 0, 1, []byte{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteMuxFrame(buf, 0, 2, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CombineMux(buf, 2, new(bytes.Buffer)); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func TestSplitInterleaved(t *testing.T) {
+	for _, secret := range [][]byte{[]byte("well hello there!"), {}} {
+		b, err := SplitInterleaved(5, 3, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v, want := len(b), 5*(5+len(secret)); v != want {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+
+		shares, err := DeinterleaveShares(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v, want := ShareSet(shares).IDs(), []byte{1, 2, 3, 4, 5}; !bytes.Equal(v, want) {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+
+		subset, err := ShareSet(shares).Subset(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v := Combine(subset); !bytes.Equal(v, secret) {
+			t.Errorf("Was %v, but expected %v", v, secret)
+		}
+	}
+}
+
+func TestDeinterleaveSharesTruncated(t *testing.T) {
+	b, err := SplitInterleaved(3, 2, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range []int{len(b) - 1, len(b) - 8, 3} {
+		if _, err := DeinterleaveShares(b[:l]); err != io.ErrUnexpectedEOF {
+			t.Errorf("%v bytes: was %v, but expected %v", l, err, io.ErrUnexpectedEOF)
+		}
+	}
+}
+
+func TestDeinterleaveSharesInvalidIDs(t *testing.T) {
+	if _, err := DeinterleaveShares([]byte{0, 0, 0, 0, 0}); err != ErrInvalidID {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidID)
+	}
+
+	if _, err := DeinterleaveShares([]byte{1, 0, 0, 0, 0, 1, 0, 0, 0, 0}); err != ErrDuplicateID {
+		t.Errorf("Was %v, but expected %v", err, ErrDuplicateID)
+	}
+}
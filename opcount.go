@@ -0,0 +1,25 @@
+//go:build sssopcount
+
+package sss
+
+import (
+	"sync/atomic"
+)
+
+// counts of field operations, maintained only when built with the sssopcount
+// tag so that tests and benchmarks can measure the work an operation performs
+var muls, divs atomic.Uint64
+
+func countMul() {
+	muls.Add(1)
+}
+
+func countDiv() {
+	divs.Add(1)
+}
+
+// returns the number of multiplications and divisions performed since the
+// last call, and resets the counts
+func resetOpCounts() (uint64, uint64) {
+	return muls.Swap(0), divs.Swap(0)
+}
@@ -0,0 +1,9 @@
+//go:build !sssopcount
+
+package sss
+
+// without the sssopcount tag, counting compiles away entirely
+
+func countMul() {}
+
+func countDiv() {}
@@ -0,0 +1,61 @@
+//go:build sssopcount
+
+package sss
+
+import (
+	"testing"
+)
+
+func TestSplitOpCount(t *testing.T) {
+	secret := make([]byte, 100)
+
+	resetOpCounts()
+	if _, err := Split(5, 3, secret); err != nil {
+		t.Fatal(err)
+	}
+	m, d := resetOpCounts()
+
+	if v, want := int(m+d), EstimateSplitCost(5, 3, len(secret)); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestCombineOpCount(t *testing.T) {
+	shares, err := Split(5, 3, make([]byte, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resetOpCounts()
+	Combine(subset)
+	m, d := resetOpCounts()
+
+	if v, want := int(m+d), EstimateCombineCost(3, 100); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestCombinePairOpCount(t *testing.T) {
+	shares, err := Split(5, 2, make([]byte, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resetOpCounts()
+	Combine(subset)
+	m, d := resetOpCounts()
+
+	if v, want := int(m+d), EstimateCombineCost(2, 100); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
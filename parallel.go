@@ -0,0 +1,78 @@
+package sss
+
+import (
+	"crypto/rand"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// SplitConcurrent splits the given secret into N shares of which K are
+// required to recover the secret, as with Split, but evaluates the polynomials
+// on the given number of goroutines. If workers is zero or negative,
+// runtime.GOMAXPROCS(0) goroutines are used.
+//
+// The random coefficients for every polynomial are read in a single bulk read
+// before any evaluation begins, so the shares are drawn from the same
+// distribution as those of Split, but SplitConcurrent consumes randomness in a
+// different order and will not produce the same shares as Split given the same
+// random bytes.
+func SplitConcurrent(n, k byte, secret []byte, workers int) (map[byte][]byte, error) {
+	if err := Validate(int(n), int(k), len(secret)); err != nil {
+		return nil, err
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// the non-constant coefficients of each byte's polynomial, in order
+	degree := int(k) - 1
+	coeffs := make([]byte, len(secret)*degree)
+	if _, err := io.ReadFull(rand.Reader, coeffs); err != nil {
+		return nil, err
+	}
+
+	// the Nth term can't be zero, or else it's a (N-1) degree polynomial
+	for i := degree - 1; i < len(coeffs); i += degree {
+		if err := redrawZero(coeffs[i:i+1], rand.Reader); err != nil {
+			return nil, err
+		}
+	}
+
+	ys := make([][]byte, int(n)+1)
+	buf := make([]byte, int(n)*len(secret))
+	for x := 1; x <= int(n); x++ {
+		ys[x] = buf[(x-1)*len(secret) : x*len(secret) : x*len(secret)]
+	}
+
+	chunk := (len(secret) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(secret); start += chunk {
+		end := start + chunk
+		if end > len(secret) {
+			end = len(secret)
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+
+			p := make([]byte, k)
+			for i := start; i < end; i++ {
+				p[0] = secret[i]
+				copy(p[1:], coeffs[i*degree:(i+1)*degree])
+				for x := 1; x <= int(n); x++ {
+					ys[x][i] = eval(p, byte(x))
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	shares := make(map[byte][]byte, n)
+	for x := 1; x <= int(n); x++ {
+		shares[byte(x)] = ys[x]
+	}
+	return shares, nil
+}
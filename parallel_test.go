@@ -0,0 +1,72 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSplitConcurrent(t *testing.T) {
+	secret := make([]byte, 10000)
+	if _, err := rand.Read(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{0, 1, 3, 20000} {
+		shares, err := SplitConcurrent(5, 3, secret, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		subset, err := ShareSet(shares).Subset(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v := Combine(subset); !bytes.Equal(v, secret) {
+			t.Errorf("%v workers didn't recover the secret", workers)
+		}
+	}
+}
+
+func TestSplitConcurrentEmpty(t *testing.T) {
+	shares, err := SplitConcurrent(3, 2, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := len(shares), 3; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestSplitConcurrentInvalidParams(t *testing.T) {
+	if _, err := SplitConcurrent(5, 1, nil, 0); !errors.Is(err, ErrInvalidThreshold) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidThreshold)
+	}
+
+	if _, err := SplitConcurrent(2, 3, nil, 0); err != ErrInvalidCount {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidCount)
+	}
+}
+
+func BenchmarkSplitSerial(b *testing.B) {
+	secret := make([]byte, 1024*1024)
+	b.SetBytes(int64(len(secret)))
+	for i := 0; i < b.N; i++ {
+		if _, err := Split(5, 3, secret); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSplitConcurrent(b *testing.B) {
+	secret := make([]byte, 1024*1024)
+	b.SetBytes(int64(len(secret)))
+	for i := 0; i < b.N; i++ {
+		if _, err := SplitConcurrent(5, 3, secret, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
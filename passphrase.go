@@ -0,0 +1,115 @@
+package sss
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// the Argon2id time and memory (in KiB) costs and parallelism used to
+	// derive each key, as recommended by RFC 9106 for memory-constrained uses
+	passphraseTime    = 3
+	passphraseMemory  = 64 * 1024
+	passphraseThreads = 4
+	// the largest costs DecryptShare accepts, so that a crafted share cannot
+	// demand unbounded work
+	passphraseMaxTime   = 16
+	passphraseMaxMemory = 1024 * 1024
+	// the size of the Argon2id parameters stored with each encrypted share
+	passphraseParamsSize = 4 + 4 + 1
+	// the size of the random salt for each encrypted share
+	passphraseSaltSize = 16
+	// the ID, parameters, salt, nonce, and GCM tag which accompany an
+	// encrypted share
+	passphraseOverhead = 1 + passphraseParamsSize + passphraseSaltSize + 12 + 16
+)
+
+// EncryptShare encrypts the share with the given ID under a passphrase, so
+// that each party can protect their own share at rest. A key is derived from
+// the passphrase and a random salt with Argon2id, a memory-hard function, and
+// the share is encrypted with AES-256-GCM, authenticating its ID and the
+// Argon2id parameters along with its value.
+//
+// The encrypted share is the ID, the Argon2id time cost (big-endian uint32),
+// memory cost in KiB (big-endian uint32), and parallelism (one byte), the
+// salt, the nonce, and the ciphertext, including the GCM tag. Storing the
+// parameters allows them to be raised later without breaking existing shares.
+// Use DecryptShare to recover the share before combining it.
+//
+// N.B.: The encryption is only as strong as the passphrase. Argon2id slows
+// guessing, but does not make a weak passphrase safe.
+func EncryptShare(id byte, y []byte, passphrase string) ([]byte, error) {
+	b := make([]byte, 1+passphraseParamsSize+passphraseSaltSize, passphraseOverhead+len(y))
+	b[0] = id
+	binary.BigEndian.PutUint32(b[1:], passphraseTime)
+	binary.BigEndian.PutUint32(b[5:], passphraseMemory)
+	b[9] = passphraseThreads
+
+	salt := b[1+passphraseParamsSize:]
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := passphraseAEAD(passphrase, salt, passphraseTime, passphraseMemory, passphraseThreads)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	header := b[:1+passphraseParamsSize]
+	b = append(b, nonce...)
+	return aead.Seal(b, nonce, y, header), nil
+}
+
+// DecryptShare decrypts a share encrypted with EncryptShare, returning its ID
+// and value. Returns ErrAuthentication if the passphrase is wrong or the
+// encrypted share has been altered, and ErrMalformedShare if it is truncated
+// or its Argon2id parameters are out of range.
+func DecryptShare(b []byte, passphrase string) (byte, []byte, error) {
+	if len(b) < passphraseOverhead {
+		return 0, nil, ErrMalformedShare
+	}
+
+	id, header := b[0], b[:1+passphraseParamsSize]
+	passes := binary.BigEndian.Uint32(b[1:])
+	memory := binary.BigEndian.Uint32(b[5:])
+	threads := b[9]
+	if passes == 0 || passes > passphraseMaxTime || memory == 0 || memory > passphraseMaxMemory || threads == 0 {
+		return 0, nil, ErrMalformedShare
+	}
+
+	salt := b[1+passphraseParamsSize : 1+passphraseParamsSize+passphraseSaltSize]
+	aead, err := passphraseAEAD(passphrase, salt, passes, memory, threads)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rest := b[1+passphraseParamsSize+passphraseSaltSize:]
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+
+	y, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return 0, nil, ErrAuthentication
+	}
+	return id, y, nil
+}
+
+// returns an AES-256-GCM cipher keyed from the passphrase and salt
+func passphraseAEAD(passphrase string, salt []byte, passes, memory uint32, threads uint8) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), salt, passes, memory, threads, 32)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
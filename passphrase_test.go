@@ -0,0 +1,108 @@
+package sss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptShare(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(3, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	passphrases := map[byte]string{1: "alpha", 2: "bravo", 3: "charlie"}
+
+	decrypted := make(map[byte][]byte, len(shares))
+	for x, y := range shares {
+		b, err := EncryptShare(x, y, passphrases[x])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v, want := len(b), passphraseOverhead+len(y); v != want {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+
+		id, v, err := DecryptShare(b, passphrases[x])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != x {
+			t.Errorf("Was %v, but expected %v", id, x)
+		}
+		decrypted[id] = v
+	}
+
+	if v := Combine(decrypted); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestDecryptShareWrongPassphrase(t *testing.T) {
+	b, err := EncryptShare(1, []byte("well hello there!"), "alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := DecryptShare(b, "bravo"); err != ErrAuthentication {
+		t.Errorf("Was %v, but expected %v", err, ErrAuthentication)
+	}
+}
+
+func TestDecryptShareAlteredID(t *testing.T) {
+	b, err := EncryptShare(1, []byte("well hello there!"), "alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b[0] = 2
+
+	if _, _, err := DecryptShare(b, "alpha"); err != ErrAuthentication {
+		t.Errorf("Was %v, but expected %v", err, ErrAuthentication)
+	}
+}
+
+func TestDecryptShareAlteredParams(t *testing.T) {
+	b, err := EncryptShare(1, []byte("well hello there!"), "alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b[4]++ // the time cost
+
+	if _, _, err := DecryptShare(b, "alpha"); err != ErrAuthentication {
+		t.Errorf("Was %v, but expected %v", err, ErrAuthentication)
+	}
+}
+
+func TestDecryptShareParamsOutOfRange(t *testing.T) {
+	b, err := EncryptShare(1, []byte("well hello there!"), "alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		offset int
+		value  byte
+	}{
+		{4, 0},    // no passes
+		{4, 17},   // too many passes
+		{5, 0xff}, // too much memory
+		{9, 0},    // no threads
+	} {
+		altered := append([]byte(nil), b...)
+		altered[c.offset] = c.value
+
+		if _, _, err := DecryptShare(altered, "alpha"); err != ErrMalformedShare {
+			t.Errorf("%v: was %v, but expected %v", c, err, ErrMalformedShare)
+		}
+	}
+}
+
+func TestDecryptShareTruncated(t *testing.T) {
+	if _, _, err := DecryptShare(make([]byte, passphraseOverhead-1), "alpha"); err != ErrMalformedShare {
+		t.Errorf("Was %v, but expected %v", err, ErrMalformedShare)
+	}
+}
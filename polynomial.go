@@ -2,6 +2,11 @@ package sss
 
 import "io"
 
+// the most times the leading coefficient of a polynomial is drawn before the
+// source of randomness is deemed broken; a uniform source draws zero this many
+// times in a row with probability 2^-1024
+const maxCoefficientDraws = 128
+
 // the degree of the polynomial
 func degree(p []byte) int {
 	return len(p) - 1
@@ -9,9 +14,9 @@ func degree(p []byte) int {
 
 // evaluate the polynomial at the given point
 func eval(p []byte, x byte) (result byte) {
-	// Horner's scheme
-	for i := 1; i <= len(p); i++ {
-		result = mul(result, x) ^ p[len(p)-i]
+	// Horner's scheme, from the highest-degree coefficient down
+	for i := len(p) - 1; i >= 0; i-- {
+		result = mul(result, x) ^ p[i]
 	}
 	return
 }
@@ -21,27 +26,30 @@ func generate(degree byte, x byte, rand io.Reader) ([]byte, error) {
 	result := make([]byte, degree+1)
 	result[0] = x
 
-	buf := make([]byte, degree-1)
-	if _, err := io.ReadFull(rand, buf); err != nil {
+	if _, err := io.ReadFull(rand, result[1:]); err != nil {
 		return nil, err
 	}
 
-	for i := byte(1); i < degree; i++ {
-		result[i] = buf[i-1]
+	// the Nth term can't be zero, or else it's a (N-1) degree polynomial
+	if err := redrawZero(result[degree:], rand); err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	// the Nth term can't be zero, or else it's a (N-1) degree polynomial
-	for {
-		buf = make([]byte, 1)
-		if _, err := io.ReadFull(rand, buf); err != nil {
-			return nil, err
+// redraws the single, already drawn byte in b while it is zero, returning
+// ErrDegenerateRandomness if maxCoefficientDraws draws in all are zero
+func redrawZero(b []byte, rand io.Reader) error {
+	for draws := 1; b[0] == 0; draws++ {
+		if draws == maxCoefficientDraws {
+			return ErrDegenerateRandomness
 		}
 
-		if buf[0] != 0 {
-			result[degree] = buf[0]
-			return result, nil
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // an input/output pair
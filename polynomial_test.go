@@ -2,7 +2,9 @@ package sss
 
 import (
 	"bytes"
+	"crypto/rand"
 	"testing"
+	"testing/iotest"
 )
 
 var (
@@ -22,6 +24,38 @@ func TestEval(t *testing.T) {
 	}
 }
 
+func TestEvalMatchesSum(t *testing.T) {
+	poly := make([]byte, 8)
+	for i := 0; i < 1000; i++ {
+		if _, err := rand.Read(poly); err != nil {
+			t.Fatal(err)
+		}
+
+		for x := 0; x < fieldSize; x++ {
+			// sum each term, a_i * x^i, independently
+			var want byte
+			for j, a := range poly {
+				term := a
+				for k := 0; k < j; k++ {
+					term = mul(term, byte(x))
+				}
+				want ^= term
+			}
+
+			if v := eval(poly, byte(x)); v != want {
+				t.Fatalf("eval(%v, %v) was %v, but expected %v", poly, x, v, want)
+			}
+		}
+	}
+}
+
+func BenchmarkEval(b *testing.B) {
+	poly := []byte{42, 17, 3, 200, 91}
+	for i := 0; i < b.N; i++ {
+		eval(poly, byte(i))
+	}
+}
+
 func TestGenerate(t *testing.T) {
 	b := []byte{1, 2, 3}
 
@@ -76,6 +110,24 @@ func TestGenerateFullSize(t *testing.T) {
 	}
 }
 
+func TestGenerateOneByteReader(t *testing.T) {
+	b := []byte{1, 2, 0, 0, 4}
+
+	expected, err := generate(3, 10, bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := generate(3, 10, iotest.OneByteReader(bytes.NewReader(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("Was %v, but expected %v", actual, expected)
+	}
+}
+
 func TestInterpolate(t *testing.T) {
 	in := []pair{
 		pair{x: 1, y: 1},
@@ -0,0 +1,96 @@
+package sss
+
+const (
+	// the number of parity bytes appended to each block by EncodeShareRS
+	rsParitySize = 2
+	// the number of share bytes in each block, so that a block and its parity
+	// fill a codeword of the field's multiplicative order
+	rsDataSize = fieldSize - 1 - rsParitySize
+)
+
+// EncodeShareRS encodes a share with a Reed-Solomon code over GF(2^8), so that
+// a single corrupted byte in each block of the encoding can be corrected by
+// DecodeShareRS before the share is combined. The share is divided into blocks
+// of 253 bytes, and each block is followed by two parity bytes, so the
+// encoding is two bytes longer per block than the share.
+//
+// This protects each share against local damage in storage, independently of
+// any check performed when shares are combined.
+func EncodeShareRS(share []byte) []byte {
+	blocks := (len(share) + rsDataSize - 1) / rsDataSize
+	encoded := make([]byte, 0, len(share)+blocks*rsParitySize)
+	for len(share) > 0 {
+		l := min(len(share), rsDataSize)
+		encoded = append(encoded, share[:l]...)
+		encoded = append(encoded, rsParity(share[:l])...)
+		share = share[l:]
+	}
+	return encoded
+}
+
+// DecodeShareRS decodes a share encoded with EncodeShareRS, correcting up to
+// one corrupted byte in each block. Returns ErrCorruptShare if a block is
+// found to have more errors than can be corrected, or ErrMalformedShare if the
+// encoding is truncated.
+//
+// N.B.: A block with more than one corrupted byte is usually, but not always,
+// detected; some such blocks are miscorrected instead.
+func DecodeShareRS(encoded []byte) ([]byte, error) {
+	share := make([]byte, 0, len(encoded))
+	for len(encoded) > 0 {
+		l := min(len(encoded), rsDataSize+rsParitySize)
+		if l <= rsParitySize {
+			return nil, ErrMalformedShare
+		}
+
+		block := append([]byte(nil), encoded[:l]...)
+		if err := rsCorrect(block); err != nil {
+			return nil, err
+		}
+
+		share = append(share, block[:l-rsParitySize]...)
+		encoded = encoded[l:]
+	}
+	return share, nil
+}
+
+// returns the parity bytes for a block, chosen so that the codeword's
+// syndromes, the sums of c_i and of c_i*3^i over its bytes c_i, are both zero
+func rsParity(data []byte) []byte {
+	var a, b byte
+	for i, d := range data {
+		a ^= d
+		b ^= mul(d, exp[i])
+	}
+
+	// the parity bytes p0 and p1 sit at positions L and L+1, so must satisfy
+	// p0+p1 = a and p0*3^L + p1*3^(L+1) = b
+	l := len(data)
+	p0 := div(b^mul(a, exp[l+1]), mul(exp[l], 1^exp[1]))
+	return []byte{p0, a ^ p0}
+}
+
+// corrects up to one error in the codeword in place
+func rsCorrect(c []byte) error {
+	var s0, s1 byte
+	for i, v := range c {
+		s0 ^= v
+		s1 ^= mul(v, exp[i])
+	}
+
+	switch {
+	case s0 == 0 && s1 == 0:
+		return nil
+	case s0 == 0 || s1 == 0:
+		return ErrCorruptShare
+	}
+
+	// a single error of value s0 at position j gives s1 = s0*3^j
+	j := int(log[div(s1, s0)])
+	if j >= len(c) {
+		return ErrCorruptShare
+	}
+
+	c[j] ^= s0
+	return nil
+}
@@ -0,0 +1,74 @@
+package sss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestShareRSRoundtrip(t *testing.T) {
+	for _, l := range []int{0, 1, 17, rsDataSize, rsDataSize + 1, 3*rsDataSize + 10} {
+		share := make([]byte, l)
+		for i := range share {
+			share[i] = byte(i * 31)
+		}
+
+		encoded := EncodeShareRS(share)
+		blocks := (l + rsDataSize - 1) / rsDataSize
+		if v, want := len(encoded), l+blocks*rsParitySize; v != want {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+
+		v, err := DecodeShareRS(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(v, share) {
+			t.Errorf("Was %v, but expected %v", v, share)
+		}
+	}
+}
+
+func TestShareRSCorrectsOneError(t *testing.T) {
+	shares, err := Split(3, 2, bytes.Repeat([]byte("well hello there!"), 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := EncodeShareRS(shares[1])
+
+	// flip one byte at every position, including the parity bytes, in turn
+	for i := range encoded {
+		damaged := append([]byte(nil), encoded...)
+		damaged[i] ^= 0x5a
+
+		v, err := DecodeShareRS(damaged)
+		if err != nil {
+			t.Fatalf("Position %v: %v", i, err)
+		}
+
+		if !bytes.Equal(v, shares[1]) {
+			t.Fatalf("Position %v was not corrected", i)
+		}
+	}
+}
+
+func TestShareRSDetectsTwoErrors(t *testing.T) {
+	encoded := EncodeShareRS([]byte("well hello there!"))
+
+	// two identical flips cancel out in the first syndrome but not the second
+	encoded[3] ^= 0x01
+	encoded[9] ^= 0x01
+
+	if _, err := DecodeShareRS(encoded); err != ErrCorruptShare {
+		t.Errorf("Was %v, but expected %v", err, ErrCorruptShare)
+	}
+}
+
+func TestDecodeShareRSTruncated(t *testing.T) {
+	encoded := EncodeShareRS(make([]byte, rsDataSize+1))
+
+	if _, err := DecodeShareRS(encoded[:len(encoded)-1]); err != ErrMalformedShare {
+		t.Errorf("Was %v, but expected %v", err, ErrMalformedShare)
+	}
+}
@@ -0,0 +1,291 @@
+package sss
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"time"
+)
+
+const (
+	// the current version of the share encoding
+	shareVersion = 1
+	// version, ID, threshold, timestamp, label length, value length, checksum
+	shareOverhead = 1 + 1 + 1 + 8 + 2 + 4 + 4
+	// the length and its checksum which precede an encoded share on a medium
+	mediumHeaderSize = 4 + 4
+)
+
+// Meta is descriptive metadata recorded in every share of a split, allowing
+// shares to be identified without recovering the secret.
+type Meta struct {
+	Created time.Time // when the secret was split
+	Label   string    // a dealer-chosen description of the secret
+}
+
+// Share is a self-describing share, recording the share's ID, the threshold
+// required to recover the secret, and the split's metadata alongside the share
+// value.
+//
+// The binary encoding of a share is a version byte, the share ID, the
+// threshold, the creation time as big-endian Unix seconds (8 bytes), the label
+// as a 2-byte big-endian length followed by its bytes, the value as a 4-byte
+// big-endian length followed by its bytes, and a big-endian CRC-32 (IEEE) of
+// all the preceding bytes.
+//
+// N.B.: The checksum detects accidental corruption only. It does not prevent a
+// malicious party from altering a share or its metadata.
+type Share struct {
+	ID        byte
+	Threshold byte
+	Meta      Meta
+	Value     []byte
+}
+
+// SplitWithMeta splits the given secret into N shares of which K are required
+// to recover the secret, recording the given metadata in each. Returns the
+// shares in order of ID (1-255).
+func SplitWithMeta(n, k byte, secret []byte, meta Meta) ([]Share, error) {
+	if len(meta.Label) > math.MaxUint16 {
+		return nil, ErrLabelTooLong
+	}
+
+	values, err := Split(n, k, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share, n)
+	for i := range shares {
+		id := byte(i + 1)
+		shares[i] = Share{ID: id, Threshold: k, Meta: meta, Value: values[id]}
+	}
+	return shares, nil
+}
+
+// SplitToSize splits the given secret into N shares of which K are required to
+// recover the secret, as with SplitWithMeta with empty metadata, but first
+// checks that each encoded share will fit within maxShareBytes. Returns the
+// shares and the size of each encoded share, or ErrShareTooLarge if they would
+// not fit.
+func SplitToSize(n, k byte, secret []byte, maxShareBytes int) ([]Share, int, error) {
+	size := shareOverhead + len(secret)
+	if size > maxShareBytes {
+		return nil, 0, ErrShareTooLarge
+	}
+
+	shares, err := SplitWithMeta(n, k, secret, Meta{})
+	if err != nil {
+		return nil, 0, err
+	}
+	return shares, size, nil
+}
+
+// SplitForMedium splits the given secret into N shares of which K are required
+// to recover the secret, and encodes each to fill exactly mediumBytes bytes,
+// such as for provisioning fixed-capacity hardware tokens. Returns the bytes
+// to write to each token, in order of share ID (1-255), or ErrShareTooLarge if
+// the shares would not fit.
+//
+// Each token holds the length of the share's binary encoding as a big-endian
+// uint32, a big-endian CRC-32 (IEEE) of that length, the encoded share, with
+// empty metadata, and zeros up to mediumBytes. Both the length and the encoded
+// share are checksummed, so damage to either is detected when the token is
+// read with CombineFromMedium.
+func SplitForMedium(n, k byte, secret []byte, mediumBytes int) ([][]byte, error) {
+	shares, size, err := SplitToSize(n, k, secret, mediumBytes-mediumHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([][]byte, len(shares))
+	for i := range shares {
+		b, err := shares[i].MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		token := make([]byte, 4, mediumBytes)
+		binary.BigEndian.PutUint32(token, uint32(size))
+		token = binary.BigEndian.AppendUint32(token, crc32.ChecksumIEEE(token))
+		token = append(token, b...)
+		tokens[i] = token[:mediumBytes]
+	}
+	return tokens, nil
+}
+
+// CombineFromMedium decodes tokens written by SplitForMedium and combines them
+// into the original secret, as with CombineShares. Returns ErrMalformedShare if
+// a token's length field is out of range, and ErrCorruptShare if the length or
+// the share fails its checksum.
+func CombineFromMedium(tokens [][]byte) ([]byte, error) {
+	shares := make([]Share, len(tokens))
+	for i, token := range tokens {
+		if len(token) < mediumHeaderSize {
+			return nil, ErrMalformedShare
+		}
+
+		l := binary.BigEndian.Uint32(token)
+		if uint64(l) > uint64(len(token)-mediumHeaderSize) {
+			return nil, ErrMalformedShare
+		}
+
+		if crc32.ChecksumIEEE(token[:4]) != binary.BigEndian.Uint32(token[4:]) {
+			return nil, ErrCorruptShare
+		}
+
+		if err := shares[i].UnmarshalBinary(token[mediumHeaderSize : mediumHeaderSize+l]); err != nil {
+			return nil, err
+		}
+	}
+	return CombineShares(shares)
+}
+
+// MaxSecretSize returns the length of the longest secret whose shares, with
+// empty metadata, encode to at most maxShareBytes bytes. Returns zero if even
+// an empty secret's shares would not fit.
+func MaxSecretSize(maxShareBytes int) int {
+	if maxShareBytes < shareOverhead {
+		return 0
+	}
+	return maxShareBytes - shareOverhead
+}
+
+// EncodedSize returns the length of the share's binary encoding.
+func (s *Share) EncodedSize() int {
+	return shareOverhead + len(s.Meta.Label) + len(s.Value)
+}
+
+// CombineShares combines the given self-describing shares into the original
+// secret. Returns ErrMixedShares if the shares have differing thresholds or
+// metadata, and ErrTooFewShares if there are fewer shares than the threshold.
+//
+// N.B.: As with Combine, there is no way to know whether the returned value
+// is, in fact, the original secret.
+func CombineShares(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrTooFewShares
+	}
+
+	ids := make([]byte, len(shares))
+	payloads := make([][]byte, len(shares))
+	for i, s := range shares {
+		if s.Threshold != shares[0].Threshold || !s.Meta.equal(shares[0].Meta) {
+			return nil, ErrMixedShares
+		}
+		ids[i], payloads[i] = s.ID, s.Value
+	}
+
+	if len(shares) < int(shares[0].Threshold) {
+		return nil, ErrTooFewShares
+	}
+
+	return CombineIndexed(ids, payloads)
+}
+
+// CombineSmart decodes the given encoded shares, discards any which fail their
+// checksum or cannot be decoded, and combines the rest into the original
+// secret, as with CombineShares. Returns the indexes of the discarded shares,
+// in ascending order, along with the secret, or ErrTooFewShares if fewer than
+// K shares survive.
+//
+// N.B.: The checksum detects accidental corruption only, so a share altered by
+// a malicious party may survive and corrupt the secret.
+func CombineSmart(serializedShares [][]byte, k byte) ([]byte, []int, error) {
+	var shares []Share
+	var discarded []int
+	for i, b := range serializedShares {
+		var s Share
+		if err := s.UnmarshalBinary(b); err != nil {
+			discarded = append(discarded, i)
+			continue
+		}
+		shares = append(shares, s)
+	}
+
+	if len(shares) == 0 || len(shares) < int(k) {
+		return nil, discarded, ErrTooFewShares
+	}
+
+	secret, err := CombineShares(shares)
+	return secret, discarded, err
+}
+
+// MarshalBinary encodes the share.
+func (s *Share) MarshalBinary() ([]byte, error) {
+	if len(s.Meta.Label) > math.MaxUint16 {
+		return nil, ErrLabelTooLong
+	}
+
+	var created int64
+	if !s.Meta.Created.IsZero() {
+		created = s.Meta.Created.Unix()
+	}
+
+	b := make([]byte, 0, s.EncodedSize())
+	b = append(b, shareVersion, s.ID, s.Threshold)
+	b = binary.BigEndian.AppendUint64(b, uint64(created))
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s.Meta.Label)))
+	b = append(b, s.Meta.Label...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s.Value)))
+	b = append(b, s.Value...)
+	b = binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(b))
+	return b, nil
+}
+
+// UnmarshalBinary decodes the share. Returns ErrUnsupportedVersion if the
+// share was encoded by a newer version of this package, and ErrCorruptShare if
+// the share fails its checksum.
+func (s *Share) UnmarshalBinary(data []byte) error {
+	// the version is checked first, as it determines the rest of the layout,
+	// including where the checksum is
+	if len(data) > 0 && data[0] > shareVersion {
+		return ErrUnsupportedVersion
+	}
+	return s.unmarshalV1(data)
+}
+
+// decodes a version 1 share
+func (s *Share) unmarshalV1(data []byte) error {
+	if len(data) < shareOverhead {
+		return ErrMalformedShare
+	}
+
+	body, sum := data[:len(data)-4], data[len(data)-4:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(sum) {
+		return ErrCorruptShare
+	}
+
+	if body[0] != shareVersion {
+		return ErrMalformedShare
+	}
+
+	id, threshold := body[1], body[2]
+	created := int64(binary.BigEndian.Uint64(body[3:]))
+	body = body[11:]
+
+	l := int(binary.BigEndian.Uint16(body))
+	body = body[2:]
+	if len(body) < l+4 {
+		return ErrMalformedShare
+	}
+	label := string(body[:l])
+	body = body[l:]
+
+	if uint64(binary.BigEndian.Uint32(body)) != uint64(len(body)-4) {
+		return ErrMalformedShare
+	}
+	value := append([]byte(nil), body[4:]...)
+
+	s.ID, s.Threshold, s.Value = id, threshold, value
+	s.Meta = Meta{Label: label}
+	if created != 0 {
+		s.Meta.Created = time.Unix(created, 0).UTC()
+	}
+	return nil
+}
+
+// compare metadata to the precision it is encoded with
+func (m Meta) equal(o Meta) bool {
+	return m.Label == o.Label && m.Created.Unix() == o.Created.Unix()
+}
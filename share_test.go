@@ -0,0 +1,313 @@
+package sss
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var meta = Meta{
+	Created: time.Date(2014, 3, 14, 15, 9, 26, 0, time.UTC),
+	Label:   "root key",
+}
+
+func TestSplitWithMeta(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := SplitWithMeta(5, 3, secret, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []Share
+	for _, s := range shares[1:4] {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var d Share
+		if err := d.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		decoded = append(decoded, d)
+	}
+
+	actual, err := CombineShares(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Errorf("Was %v, but expected %v", actual, secret)
+	}
+}
+
+func TestShareUnmarshalMeta(t *testing.T) {
+	shares, err := SplitWithMeta(2, 2, []byte("secret"), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := shares[1].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s Share
+	if err := s.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := s.ID, byte(2); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if v, want := s.Threshold, byte(2); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if v, want := s.Meta, meta; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestShareUnmarshalZeroMeta(t *testing.T) {
+	in := Share{ID: 1, Threshold: 2, Value: []byte{1, 2, 3}}
+
+	b, err := in.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s Share
+	if err := s.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := s.Meta, (Meta{}); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestShareUnmarshalCorrupt(t *testing.T) {
+	in := Share{ID: 1, Threshold: 2, Meta: meta, Value: []byte{1, 2, 3}}
+
+	b, err := in.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b[5] ^= 1
+
+	var s Share
+	if err := s.UnmarshalBinary(b); err != ErrCorruptShare {
+		t.Errorf("Was %v, but expected %v", err, ErrCorruptShare)
+	}
+}
+
+func TestShareUnmarshalNewerVersion(t *testing.T) {
+	in := Share{ID: 1, Threshold: 2, Meta: meta, Value: []byte{1, 2, 3}}
+
+	b, err := in.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []byte{shareVersion + 1, 0xff} {
+		b[0] = v
+
+		var s Share
+		if err := s.UnmarshalBinary(b); err != ErrUnsupportedVersion {
+			t.Errorf("Was %v, but expected %v", err, ErrUnsupportedVersion)
+		}
+	}
+}
+
+func TestShareUnmarshalShort(t *testing.T) {
+	var s Share
+	if err := s.UnmarshalBinary([]byte{1, 2, 3}); err != ErrMalformedShare {
+		t.Errorf("Was %v, but expected %v", err, ErrMalformedShare)
+	}
+}
+
+func TestCombineSharesMixed(t *testing.T) {
+	a, err := SplitWithMeta(2, 2, []byte("secret"), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := SplitWithMeta(2, 2, []byte("secret"), Meta{Label: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CombineShares([]Share{a[0], b[1]}); err != ErrMixedShares {
+		t.Errorf("Was %v, but expected %v", err, ErrMixedShares)
+	}
+}
+
+func TestCombineSharesTooFew(t *testing.T) {
+	shares, err := SplitWithMeta(3, 3, []byte("secret"), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CombineShares(shares[:2]); err != ErrTooFewShares {
+		t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+	}
+}
+
+func TestSplitToSize(t *testing.T) {
+	max := 64
+	secret := make([]byte, MaxSecretSize(max))
+
+	shares, size, err := SplitToSize(3, 2, secret, max)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := size, max; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	for _, s := range shares {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v, want := len(b), size; v != want {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+	}
+}
+
+func TestSplitToSizeTooLarge(t *testing.T) {
+	max := 64
+	secret := make([]byte, MaxSecretSize(max)+1)
+
+	if _, _, err := SplitToSize(3, 2, secret, max); err != ErrShareTooLarge {
+		t.Errorf("Was %v, but expected %v", err, ErrShareTooLarge)
+	}
+}
+
+func TestMaxSecretSize(t *testing.T) {
+	if v, want := MaxSecretSize(10), 0; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestShareEncodedSize(t *testing.T) {
+	s := Share{ID: 1, Threshold: 2, Meta: meta, Value: []byte{1, 2, 3}}
+
+	b, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := s.EncodedSize(), len(b); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestSplitForMedium(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	for _, medium := range []int{mediumHeaderSize + shareOverhead + len(secret), 128} {
+		tokens, err := SplitForMedium(5, 3, secret, medium)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, token := range tokens {
+			if v, want := len(token), medium; v != want {
+				t.Errorf("Token %v was %v bytes, but expected %v", i, v, want)
+			}
+		}
+
+		v, err := CombineFromMedium(tokens[1:4])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(v, secret) {
+			t.Errorf("Was %v, but expected %v", v, secret)
+		}
+	}
+}
+
+func TestSplitForMediumTooSmall(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	if _, err := SplitForMedium(5, 3, secret, mediumHeaderSize+shareOverhead+len(secret)-1); err != ErrShareTooLarge {
+		t.Errorf("Was %v, but expected %v", err, ErrShareTooLarge)
+	}
+}
+
+func TestCombineFromMediumCorrupt(t *testing.T) {
+	tokens, err := SplitForMedium(5, 3, []byte("well hello there!"), 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens[0][mediumHeaderSize+3]++
+	if _, err := CombineFromMedium(tokens); err != ErrCorruptShare {
+		t.Errorf("Was %v, but expected %v", err, ErrCorruptShare)
+	}
+
+	tokens[0][0] = 0xff
+	if _, err := CombineFromMedium(tokens); err != ErrMalformedShare {
+		t.Errorf("Was %v, but expected %v", err, ErrMalformedShare)
+	}
+}
+
+func TestCombineFromMediumCorruptLength(t *testing.T) {
+	tokens, err := SplitForMedium(5, 3, []byte("well hello there!"), 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a length which is still in range, but not the one written
+	tokens[1][3]--
+	if _, err := CombineFromMedium(tokens); err != ErrCorruptShare {
+		t.Errorf("Was %v, but expected %v", err, ErrCorruptShare)
+	}
+}
+
+func TestCombineSmart(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := SplitWithMeta(5, 3, secret, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := make([][]byte, len(shares))
+	for i := range shares {
+		if encoded[i], err = shares[i].MarshalBinary(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	encoded[0][len(encoded[0])-8] ^= 1
+	encoded[3][5] ^= 1
+
+	v, discarded, err := CombineSmart(encoded, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+
+	if v, want := fmt.Sprint(discarded), "[0 3]"; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	encoded[1] = encoded[1][:3]
+	if _, _, err := CombineSmart(encoded, 3); err != ErrTooFewShares {
+		t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+	}
+}
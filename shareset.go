@@ -0,0 +1,94 @@
+package sss
+
+import (
+	"bytes"
+	"io"
+	"sort"
+)
+
+// ShareSet is a set of shares, keyed by share ID.
+type ShareSet map[byte][]byte
+
+// IDs returns the share IDs in the set, in ascending order.
+func (s ShareSet) IDs() []byte {
+	ids := make([]byte, 0, len(s))
+	for id := range s {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Subset returns the K shares with the lowest IDs in the set.
+func (s ShareSet) Subset(k byte) (map[byte][]byte, error) {
+	if len(s) < int(k) {
+		return nil, ErrTooFewShares
+	}
+
+	subset := make(map[byte][]byte, k)
+	for _, id := range s.IDs()[:k] {
+		subset[id] = s[id]
+	}
+	return subset, nil
+}
+
+// RandomSubset returns K shares chosen uniformly at random from the set, using
+// the given source of randomness.
+func (s ShareSet) RandomSubset(k byte, rand io.Reader) (map[byte][]byte, error) {
+	if len(s) < int(k) {
+		return nil, ErrTooFewShares
+	}
+
+	// partial Fisher-Yates shuffle
+	ids := s.IDs()
+	for i := 0; i < int(k); i++ {
+		j, err := randIndex(rand, len(ids)-i)
+		if err != nil {
+			return nil, err
+		}
+		ids[i], ids[i+j] = ids[i+j], ids[i]
+	}
+
+	subset := make(map[byte][]byte, k)
+	for _, id := range ids[:k] {
+		subset[id] = s[id]
+	}
+	return subset, nil
+}
+
+// Merge adds the shares in other to the set. A share present in both must have
+// the same value in each; if any does not, Merge returns ErrConflictingShares
+// and leaves the set unchanged, as one of the sources must be wrong. A nil set
+// is allocated as needed.
+func (s *ShareSet) Merge(other ShareSet) error {
+	for id, y := range other {
+		if x, ok := (*s)[id]; ok && !bytes.Equal(x, y) {
+			return ErrConflictingShares
+		}
+	}
+
+	if *s == nil && len(other) > 0 {
+		*s = make(ShareSet, len(other))
+	}
+
+	for id, y := range other {
+		(*s)[id] = y
+	}
+	return nil
+}
+
+// returns a uniformly random integer in [0, n), for 0 < n <= 256
+func randIndex(rand io.Reader, n int) (int, error) {
+	// reject values which would bias the result towards lower indexes
+	max := 256 - 256%n
+	buf := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return 0, err
+		}
+
+		if int(buf[0]) < max {
+			return int(buf[0]) % n, nil
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package sss
+
+import (
+	"bytes"
+	"testing"
+)
+
+var set = ShareSet{
+	1: []byte{1},
+	4: []byte{4},
+	2: []byte{2},
+	9: []byte{9},
+}
+
+func TestShareSetIDs(t *testing.T) {
+	if v, want := set.IDs(), []byte{1, 2, 4, 9}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestShareSetSubset(t *testing.T) {
+	subset, err := set.Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := ShareSet(subset).IDs(), []byte{1, 2, 4}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestShareSetSubsetTooFew(t *testing.T) {
+	if _, err := set.Subset(5); err != ErrTooFewShares {
+		t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+	}
+}
+
+func TestShareSetRandomSubset(t *testing.T) {
+	// picks index 3 of 4, then rejects 255 as biased for 3 and picks index 0 of
+	// 3, then picks index 1 of 2
+	r := bytes.NewReader([]byte{3, 255, 3, 1})
+
+	subset, err := set.RandomSubset(3, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := ShareSet(subset).IDs(), []byte{1, 2, 9}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	for id, y := range subset {
+		if !bytes.Equal(y, set[id]) {
+			t.Errorf("Share %v was %v, but expected %v", id, y, set[id])
+		}
+	}
+}
+
+func TestShareSetRandomSubsetTooFew(t *testing.T) {
+	if _, err := set.RandomSubset(5, bytes.NewReader(nil)); err != ErrTooFewShares {
+		t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+	}
+}
+
+func TestShareSetRandomSubsetEOF(t *testing.T) {
+	if _, err := set.RandomSubset(2, bytes.NewReader([]byte{0})); err == nil {
+		t.Error("No error returned")
+	}
+}
+
+func TestShareSetMerge(t *testing.T) {
+	s := ShareSet{1: {1}, 2: {2}}
+
+	// disjoint and identical overlapping shares merge
+	if err := s.Merge(ShareSet{2: {2}, 3: {3}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := s.IDs(), []byte{1, 2, 3}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestShareSetMergeNil(t *testing.T) {
+	var s ShareSet
+
+	if err := s.Merge(ShareSet{2: {2}, 1: {1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := s.IDs(), []byte{1, 2}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestShareSetMergeConflict(t *testing.T) {
+	s := ShareSet{1: {1}, 2: {2}}
+
+	if err := s.Merge(ShareSet{3: {3}, 2: {9}}); err != ErrConflictingShares {
+		t.Errorf("Was %v, but expected %v", err, ErrConflictingShares)
+	}
+
+	if v, want := s.IDs(), []byte{1, 2}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
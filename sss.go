@@ -14,12 +14,14 @@
 //     f4(x) =  91x^2 +  95x + 42
 //     etc.
 //
-// These polynomials are then evaluated for values of X > 0:
+// These polynomials are then evaluated for values of X > 0, using the
+// arithmetic of GF(2^8) (in which addition is XOR, so the results differ from
+// those over the integers):
 //
-//     f1(1) =  139
-//     f2(2) =  896
-//     f3(3) = 1140
-//     f4(4) = 1783
+//     f1(1) = 119
+//     f2(2) =  81
+//     f3(3) = 169
+//     f4(4) = 138
 //     etc.
 //
 // These (x, y) pairs are the shares given to the parties. In order to combine
@@ -40,63 +42,461 @@ package sss
 
 import (
 	"crypto/rand"
-	"errors"
-)
-
-var (
-	// ErrInvalidCount is returned when the count parameter is invalid.
-	ErrInvalidCount = errors.New("N must be >= K")
-	// ErrInvalidThreshold is returned when the threshold parameter is invalid.
-	ErrInvalidThreshold = errors.New("K must be > 1")
+	"fmt"
+	"io"
+	"sort"
 )
 
 // Split the given secret into N shares of which K are required to recover the
 // secret. Returns a map of share IDs (1-255) to shares.
 func Split(n, k byte, secret []byte) (map[byte][]byte, error) {
-	if k <= 1 {
-		return nil, ErrInvalidThreshold
-	}
+	return split(n, k, secret, rand.Reader)
+}
 
-	if n < k {
-		return nil, ErrInvalidCount
+// SplitWithReader splits the given secret into N shares of which K are
+// required to recover the secret, as with Split, but reads the random
+// coefficients of the polynomials from the given reader rather than
+// crypto/rand. Returns any error encountered reading from rand.
+//
+// N.B.: The shares are only as secret as rand is unpredictable. This is
+// intended for testing and for callers with a vetted source of randomness.
+func SplitWithReader(n, k byte, secret []byte, rand io.Reader) (map[byte][]byte, error) {
+	return split(n, k, secret, rand)
+}
+
+// split the secret using the given source of randomness
+func split(n, k byte, secret []byte, rand io.Reader) (map[byte][]byte, error) {
+	if err := Validate(int(n), int(k), len(secret)); err != nil {
+		return nil, err
 	}
 
-	shares := make(map[byte][]byte, n)
+	return splitAt(SequentialIDs(n), k, secret, rand)
+}
 
-	for _, b := range secret {
-		p, err := generate(k-1, b, rand.Reader)
+// split the secret into shares with the given IDs, which must be valid
+func splitAt(ids []byte, k byte, secret []byte, rand io.Reader) (map[byte][]byte, error) {
+	// allocate every share up front rather than growing them byte by byte
+	ys := make([][]byte, len(ids))
+	buf := make([]byte, len(ids)*len(secret))
+	for j := range ys {
+		ys[j] = buf[j*len(secret) : (j+1)*len(secret) : (j+1)*len(secret)]
+	}
+
+	for i, b := range secret {
+		p, err := generate(k-1, b, rand)
 		if err != nil {
 			return nil, err
 		}
 
-		for x := byte(1); x <= n; x++ {
-			shares[x] = append(shares[x], eval(p, x))
+		for j, x := range ids {
+			ys[j][i] = eval(p, x)
 		}
 	}
 
+	shares := make(map[byte][]byte, len(ids))
+	for j, x := range ids {
+		shares[x] = ys[j]
+	}
 	return shares, nil
 }
 
+// SplitFunc splits the given secret into N shares of which K are required to
+// recover the secret, calling fn once with each share ID and share in turn,
+// rather than returning them all at once. fn is always called in ascending
+// order of ID, from 1 through N, so the order of the shares is reproducible.
+// If fn returns an error, SplitFunc stops and returns that error.
+//
+// The share passed to fn is only valid for the duration of the call, as its
+// buffer is reused for the next share; fn must copy it to retain it.
+func SplitFunc(n, k byte, secret []byte, fn func(id byte, y []byte) error) error {
+	if err := Validate(int(n), int(k), len(secret)); err != nil {
+		return err
+	}
+
+	// every byte's polynomial, in order, drawn into a single buffer rather
+	// than allocated one by one
+	polys := make([]byte, len(secret)*int(k))
+	if _, err := io.ReadFull(rand.Reader, polys); err != nil {
+		return err
+	}
+
+	defer func() {
+		for i := range polys {
+			polys[i] = 0
+		}
+	}()
+
+	for i, b := range secret {
+		p := polys[i*int(k) : (i+1)*int(k)]
+		p[0] = b
+
+		// the Nth term can't be zero, or else it's a (N-1) degree polynomial
+		if err := redrawZero(p[k-1:], rand.Reader); err != nil {
+			return err
+		}
+	}
+
+	y := make([]byte, len(secret))
+	for x := 1; x <= int(n); x++ {
+		for i := range y {
+			y[i] = eval(polys[i*int(k):(i+1)*int(k)], byte(x))
+		}
+
+		if err := fn(byte(x), y); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Combine the given shares into the original secret.
 //
+// Combine never panics on malformed input: if the shares differ in length,
+// only as many bytes as the shortest share holds are recovered, and an empty
+// map recovers an empty secret. Use CombineChecked to detect such input.
+//
 // N.B.: There is no way to know whether the returned value is, in fact, the
 // original secret.
 func Combine(shares map[byte][]byte) []byte {
+	points := sharePoints(shares)
+
+	l := -1
+	for _, y := range shares {
+		if l < 0 || len(y) < l {
+			l = len(y)
+		}
+	}
+
 	var secret []byte
-	for _, v := range shares {
-		secret = make([]byte, len(v))
-		break
+	if l > 0 {
+		secret = make([]byte, l)
+	}
+
+	if len(points) == 2 {
+		combinePair(points[0].x, points[1].x, shares, secret)
+		return secret
 	}
 
-	points := make([]pair, len(shares))
 	for i := range secret {
-		p := 0
-		for k, v := range shares {
-			points[p] = pair{x: k, y: v[i]}
-			p++
+		for j := range points {
+			points[j].y = shares[points[j].x][i]
 		}
 		secret[i] = interpolate(points, 0)
 	}
 
+	// don't leave the last byte's share values lying around
+	for j := range points {
+		points[j].y = 0
+	}
+
 	return secret
 }
+
+// recovers the secret from the two shares with IDs x1 and x2, for which the
+// Lagrange weights at zero reduce to x2/(x1^x2) and x1/(x1^x2), so they can be
+// computed once rather than for every byte
+func combinePair(x1, x2 byte, shares map[byte][]byte, secret []byte) {
+	w1, w2 := div(x2, x1^x2), div(x1, x1^x2)
+	y1, y2 := shares[x1], shares[x2]
+	for i := range secret {
+		secret[i] = mul(y1[i], w1) ^ mul(y2[i], w2)
+	}
+}
+
+// CombineAndWipe combines the given shares into the original secret, as with
+// Combine, then overwrites every byte of every share with zero. Once it
+// returns, the secret is the only copy of the sensitive data held in the
+// caller's buffers.
+//
+// N.B.: This cannot erase copies the Go runtime may have made, such as when a
+// slice was grown or a stack was moved, or copies held elsewhere by the
+// caller.
+func CombineAndWipe(shares map[byte][]byte) []byte {
+	secret := Combine(shares)
+
+	for _, y := range shares {
+		for i := range y {
+			y[i] = 0
+		}
+	}
+
+	return secret
+}
+
+// CombineChecked combines the given shares into the original secret, as with
+// Combine, but returns an error rather than a partial result if the shares are
+// malformed: ErrTooFewShares if there are none, ErrInvalidID if any has an ID
+// of zero, and ErrInvalidLength if they differ in length.
+//
+// N.B.: There is no way to know whether the returned value is, in fact, the
+// original secret.
+func CombineChecked(shares map[byte][]byte) ([]byte, error) {
+	if err := checkShares(shares); err != nil {
+		return nil, err
+	}
+
+	return Combine(shares), nil
+}
+
+// returns an error if there are no shares, or they are malformed
+func checkShares(shares map[byte][]byte) error {
+	if len(shares) == 0 {
+		return ErrTooFewShares
+	}
+
+	l := -1
+	for x, y := range shares {
+		if x == 0 {
+			return ErrInvalidID
+		}
+
+		if l >= 0 && len(y) != l {
+			return ErrInvalidLength
+		}
+		l = len(y)
+	}
+
+	return nil
+}
+
+// CombineTrimmed combines the first secretLen bytes of each of the given shares
+// into the original secret, ignoring any trailing bytes, such as padding added
+// by storage. Returns ErrInvalidLength if any share is shorter than secretLen.
+//
+// N.B.: There is no way to know whether the returned value is, in fact, the
+// original secret.
+func CombineTrimmed(shares map[byte][]byte, secretLen int) ([]byte, error) {
+	if secretLen < 0 {
+		return nil, ErrInvalidLength
+	}
+
+	trimmed := make(map[byte][]byte, len(shares))
+	for x, y := range shares {
+		if len(y) < secretLen {
+			return nil, ErrInvalidLength
+		}
+		trimmed[x] = y[:secretLen]
+	}
+
+	return CombineChecked(trimmed)
+}
+
+// CombineUsing combines exactly the shares with the given IDs into the original
+// secret, ignoring any others, so that the shares used are chosen explicitly,
+// such as when only some parties are trusted. Returns ErrTooFewShares if fewer
+// than two IDs are given, an error wrapping ErrTooFewShares if any of them is
+// not present, ErrInvalidID or ErrDuplicateID if any is zero or repeated, and
+// otherwise validates the shares as CombineChecked does.
+//
+// N.B.: As with Combine, there is no way to know whether the returned value
+// is, in fact, the original secret.
+func CombineUsing(shares map[byte][]byte, useIDs []byte) ([]byte, error) {
+	if len(useIDs) < 2 {
+		return nil, ErrTooFewShares
+	}
+
+	subset := make(map[byte][]byte, len(useIDs))
+	for _, x := range useIDs {
+		if x == 0 {
+			return nil, ErrInvalidID
+		}
+
+		if _, ok := subset[x]; ok {
+			return nil, ErrDuplicateID
+		}
+
+		y, ok := shares[x]
+		if !ok {
+			return nil, fmt.Errorf("%w: share %d is not present", ErrTooFewShares, x)
+		}
+		subset[x] = y
+	}
+
+	return CombineChecked(subset)
+}
+
+// CombineBestEffort combines shares which may have been truncated, such as
+// when recovering from damaged storage, recovering every byte of the secret
+// covered by at least K shares. The secret is as long as the longest share,
+// and recoverable[i] reports whether secret[i] was recovered; the other bytes
+// of the secret are zero.
+//
+// N.B.: As with Combine, there is no way to know whether the recovered bytes
+// are, in fact, those of the original secret.
+func CombineBestEffort(columns map[byte][]byte, k byte) (secret []byte, recoverable []bool) {
+	var l int
+	for _, y := range columns {
+		if len(y) > l {
+			l = len(y)
+		}
+	}
+
+	secret, recoverable = make([]byte, l), make([]bool, l)
+	ids := sharePoints(columns)
+	points := make([]pair, 0, len(ids))
+	for i := range secret {
+		points = points[:0]
+		for _, p := range ids {
+			if y := columns[p.x]; i < len(y) {
+				points = append(points, pair{x: p.x, y: y[i]})
+			}
+		}
+
+		if len(points) == 0 || len(points) < int(k) {
+			continue
+		}
+
+		secret[i] = interpolate(points, 0)
+		recoverable[i] = true
+	}
+
+	// don't leave the last byte's share values lying around
+	for j := range points {
+		points[j].y = 0
+	}
+
+	return secret, recoverable
+}
+
+// returns a point for each share, ordered by share ID, so that interpolation
+// is reproducible regardless of map iteration order
+func sharePoints(shares map[byte][]byte) []pair {
+	points := make([]pair, 0, len(shares))
+	for x := range shares {
+		points = append(points, pair{x: x})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].x < points[j].x })
+	return points
+}
+
+// CombineIndexed combines the given shares into the original secret, where
+// ids[i] is the share ID of payloads[i]. Returns ErrTooFewShares if there are
+// no shares.
+func CombineIndexed(ids []byte, payloads [][]byte) ([]byte, error) {
+	if len(ids) != len(payloads) {
+		return nil, ErrMismatchedIDs
+	}
+
+	if len(ids) == 0 {
+		return nil, ErrTooFewShares
+	}
+
+	shares := make(map[byte][]byte, len(ids))
+	for i, id := range ids {
+		if id == 0 {
+			return nil, ErrInvalidID
+		}
+
+		if _, ok := shares[id]; ok {
+			return nil, ErrDuplicateID
+		}
+
+		if len(payloads[i]) != len(payloads[0]) {
+			return nil, ErrInvalidLength
+		}
+
+		shares[id] = payloads[i]
+	}
+
+	return Combine(shares), nil
+}
+
+// RegenerateShare uses at least K cooperating shares to compute the share with
+// the given ID, allowing a lost share to be replaced, or a new party to be
+// added, without recovering the secret in any one place.
+//
+// N.B.: The cooperating shares are not verified. If any of them is incorrect,
+// the regenerated share will be too.
+func RegenerateShare(cooperating map[byte][]byte, k, id byte) ([]byte, error) {
+	if err := checkThreshold(k); err != nil {
+		return nil, err
+	}
+
+	if id == 0 {
+		return nil, ErrInvalidID
+	}
+
+	if _, ok := cooperating[id]; ok {
+		return nil, ErrDuplicateID
+	}
+
+	if len(cooperating) < int(k) {
+		return nil, ErrTooFewShares
+	}
+
+	var l int
+	for _, v := range cooperating {
+		l = len(v)
+		break
+	}
+
+	for _, y := range cooperating {
+		if len(y) != l {
+			return nil, ErrInvalidLength
+		}
+	}
+
+	points := sharePoints(cooperating)
+
+	share := make([]byte, l)
+	for i := range share {
+		for j := range points {
+			points[j].y = cooperating[points[j].x][i]
+		}
+		share[i] = interpolate(points, id)
+	}
+
+	return share, nil
+}
+
+// AppendToShares extends a secret which has already been split, appending the
+// shares of the extra bytes to each of the given shares in place. K must be the
+// threshold the shares were split with. The existing bytes of each share are
+// unchanged, and the extended shares recover the original secret followed by
+// the extra bytes.
+//
+// The shares are only modified if AppendToShares succeeds.
+func AppendToShares(shares map[byte][]byte, k byte, extra []byte, rand io.Reader) error {
+	if err := checkThreshold(k); err != nil {
+		return err
+	}
+
+	if len(shares) < int(k) {
+		return ErrTooFewShares
+	}
+
+	l := -1
+	for x, y := range shares {
+		if x == 0 {
+			return ErrInvalidID
+		}
+
+		if l >= 0 && len(y) != l {
+			return ErrInvalidLength
+		}
+		l = len(y)
+	}
+
+	appended := make(map[byte][]byte, len(shares))
+	for x, y := range shares {
+		appended[x] = append(y[:len(y):len(y)], make([]byte, len(extra))...)
+	}
+
+	for i, b := range extra {
+		p, err := generate(k-1, b, rand)
+		if err != nil {
+			return err
+		}
+
+		for x, y := range appended {
+			y[l+i] = eval(p, x)
+		}
+	}
+
+	for x, y := range appended {
+		shares[x] = y
+	}
+
+	return nil
+}
@@ -1,7 +1,11 @@
 package sss
 
 import (
+	"bytes"
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"testing"
 )
 
 func Example() {
@@ -16,12 +20,10 @@ func Example() {
 	}
 
 	// select a random subset of the total shares
-	subset := make(map[byte][]byte, k)
-	for x, y := range shares { // just iterate since maps are randomized
-		subset[x] = y
-		if len(subset) == int(k) {
-			break
-		}
+	subset, err := ShareSet(shares).RandomSubset(k, rand.Reader)
+	if err != nil {
+		fmt.Println(err)
+		return
 	}
 
 	// combine two shares and recover the secret
@@ -30,3 +32,646 @@ func Example() {
 
 	// Output: well hello there!
 }
+
+func TestCombineIndexed(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []byte{2, 4, 5}
+	payloads := [][]byte{shares[2], shares[4], shares[5]}
+
+	actual, err := CombineIndexed(ids, payloads)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Errorf("Was %v, but expected %v", actual, secret)
+	}
+}
+
+func TestCombineIndexedErrors(t *testing.T) {
+	a, b := []byte{1, 2}, []byte{3, 4}
+
+	for _, c := range []struct {
+		ids      []byte
+		payloads [][]byte
+		err      error
+	}{
+		{[]byte{1}, [][]byte{a, b}, ErrMismatchedIDs},
+		{nil, nil, ErrTooFewShares},
+		{[]byte{0, 1}, [][]byte{a, b}, ErrInvalidID},
+		{[]byte{1, 1}, [][]byte{a, b}, ErrDuplicateID},
+		{[]byte{1, 2}, [][]byte{a, b[:1]}, ErrInvalidLength},
+	} {
+		if _, err := CombineIndexed(c.ids, c.payloads); err != c.err {
+			t.Errorf("Was %v, but expected %v", err, c.err)
+		}
+	}
+}
+
+func TestSplitAllSharesRequired(t *testing.T) {
+	for _, l := range []int{1, 4096} {
+		secret := bytes.Repeat([]byte{0xa5}, l)
+
+		shares, err := Split(4, 4, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v, want := len(shares), 4; v != want {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+
+		for x, y := range shares {
+			if len(y) != l {
+				t.Errorf("Share %v was %v bytes, but expected %v", x, len(y), l)
+			}
+		}
+
+		if v := Combine(shares); !bytes.Equal(v, secret) {
+			t.Errorf("Was %v, but expected %v", v, secret)
+		}
+	}
+}
+
+func TestRegenerateShare(t *testing.T) {
+	shares, err := Split(5, 3, []byte("well hello there!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cooperating := map[byte][]byte{1: shares[1], 3: shares[3], 5: shares[5]}
+
+	actual, err := RegenerateShare(cooperating, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, shares[2]) {
+		t.Errorf("Was %v, but expected %v", actual, shares[2])
+	}
+}
+
+func TestRegenerateShareErrors(t *testing.T) {
+	a, b := []byte{1, 2}, []byte{3, 4}
+
+	for _, c := range []struct {
+		shares map[byte][]byte
+		id     byte
+		err    error
+	}{
+		{map[byte][]byte{1: a, 2: b}, 0, ErrInvalidID},
+		{map[byte][]byte{1: a, 2: b}, 2, ErrDuplicateID},
+		{map[byte][]byte{1: a}, 3, ErrTooFewShares},
+		{map[byte][]byte{1: a, 2: b[:1]}, 3, ErrInvalidLength},
+	} {
+		if _, err := RegenerateShare(c.shares, 2, c.id); err != c.err {
+			t.Errorf("Was %v, but expected %v", err, c.err)
+		}
+	}
+}
+
+func TestPackageDocExample(t *testing.T) {
+	for _, c := range []struct {
+		p    []byte
+		x, y byte
+	}{
+		{[]byte{42, 19, 78}, 1, 119},
+		{[]byte{42, 171, 128}, 2, 81},
+		{[]byte{42, 3, 121}, 3, 169},
+		{[]byte{42, 95, 91}, 4, 138},
+	} {
+		if v := eval(c.p, c.x); v != c.y {
+			t.Errorf("f(%v) was %v, but expected %v", c.x, v, c.y)
+		}
+	}
+
+	points := []pair{{x: 1, y: 119}, {x: 2, y: eval([]byte{42, 19, 78}, 2)},
+		{x: 3, y: eval([]byte{42, 19, 78}, 3)}}
+	if v, want := interpolate(points, 0), byte(42); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestSplitFunc(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	var ids []byte
+	shares := make(map[byte][]byte)
+	err := SplitFunc(5, 3, secret, func(id byte, y []byte) error {
+		ids = append(ids, id)
+		shares[id] = append([]byte(nil), y...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := ids, []byte{1, 2, 3, 4, 5}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := Combine(subset); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestSplitFuncError(t *testing.T) {
+	want := errors.New("disk full")
+
+	calls := 0
+	err := SplitFunc(5, 3, []byte("secret"), func(id byte, y []byte) error {
+		calls++
+		if id == 2 {
+			return want
+		}
+		return nil
+	})
+
+	if err != want {
+		t.Errorf("Was %v, but expected %v", err, want)
+	}
+
+	if v, want := calls, 2; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestSplitFuncInvalidParams(t *testing.T) {
+	fn := func(id byte, y []byte) error { return nil }
+
+	if err := SplitFunc(5, 1, nil, fn); !errors.Is(err, ErrInvalidThreshold) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidThreshold)
+	}
+
+	if err := SplitFunc(2, 3, nil, fn); err != ErrInvalidCount {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidCount)
+	}
+}
+
+func TestSharePoints(t *testing.T) {
+	shares := map[byte][]byte{9: nil, 3: nil, 200: nil, 1: nil, 42: nil}
+
+	var ids []byte
+	for _, p := range sharePoints(shares) {
+		ids = append(ids, p.x)
+	}
+
+	if v, want := ids, []byte{1, 3, 9, 42, 200}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestAppendToShares(t *testing.T) {
+	shares, err := Split(5, 3, []byte("abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := make(map[byte][]byte, len(shares))
+	for x, y := range shares {
+		original[x] = append([]byte(nil), y...)
+	}
+
+	if err := AppendToShares(shares, 3, []byte("def"), rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+
+	for x, y := range shares {
+		if !bytes.Equal(y[:3], original[x]) {
+			t.Errorf("Share %v was %v, but expected prefix %v", x, y, original[x])
+		}
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := Combine(subset), []byte("abcdef"); !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestAppendToSharesEOF(t *testing.T) {
+	shares := map[byte][]byte{1: {1}, 2: {2}}
+
+	if err := AppendToShares(shares, 2, []byte("def"), bytes.NewReader(nil)); err == nil {
+		t.Error("No error returned")
+	}
+
+	if v, want := shares[1], []byte{1}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestAppendToSharesMismatchedLength(t *testing.T) {
+	shares := map[byte][]byte{1: {1}, 2: {2, 3}}
+
+	if err := AppendToShares(shares, 2, []byte("def"), rand.Reader); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func TestSplitLongSecret(t *testing.T) {
+	secret := bytes.Repeat([]byte{42}, 300)
+
+	shares, err := Split(5, 5, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// with four random coefficients, the chance of two positions having the
+	// same polynomial is 2^-32, so a match means randomness was reused
+	for i := 0; i+256 < len(secret); i++ {
+		same := true
+		for _, y := range shares {
+			same = same && y[i] == y[i+256]
+		}
+
+		if same {
+			t.Errorf("Bytes %v and %v have the same polynomial", i, i+256)
+		}
+	}
+
+	if v := Combine(shares); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestCombineMismatchedLength(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(3, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares[2] = shares[2][:5]
+
+	if v, want := Combine(shares), secret[:5]; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestCombineEmpty(t *testing.T) {
+	if v := Combine(nil); len(v) != 0 {
+		t.Errorf("Was %v, but expected an empty secret", v)
+	}
+}
+
+func TestCombineChecked(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(3, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := CombineChecked(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Errorf("Was %v, but expected %v", actual, secret)
+	}
+}
+
+func TestCombineCheckedErrors(t *testing.T) {
+	for _, c := range []struct {
+		shares map[byte][]byte
+		err    error
+	}{
+		{nil, ErrTooFewShares},
+		{map[byte][]byte{0: {1}, 1: {2}}, ErrInvalidID},
+		{map[byte][]byte{1: {1}, 2: {2, 3}}, ErrInvalidLength},
+	} {
+		if _, err := CombineChecked(c.shares); err != c.err {
+			t.Errorf("Was %v, but expected %v", err, c.err)
+		}
+	}
+}
+
+func TestSplitMaxShares(t *testing.T) {
+	shares, err := Split(255, 2, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := len(shares), 255; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	calls := 0
+	err = SplitFunc(255, 2, []byte("secret"), func(id byte, y []byte) error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := calls, 255; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func BenchmarkSplitFunc(b *testing.B) {
+	secret := make([]byte, 64*1024)
+	fn := func(id byte, y []byte) error { return nil }
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(secret)))
+	for i := 0; i < b.N; i++ {
+		if err := SplitFunc(5, 3, secret, fn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSplitLarge(b *testing.B) {
+	secret := make([]byte, 64*1024)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(secret)))
+	for i := 0; i < b.N; i++ {
+		if _, err := Split(5, 3, secret); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCombineTrimmed(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(3, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// pad each share with a differing number of zeros
+	shares[1] = append(shares[1], make([]byte, 15)...)
+	shares[3] = append(shares[3], make([]byte, 3)...)
+
+	actual, err := CombineTrimmed(shares, len(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Errorf("Was %v, but expected %v", actual, secret)
+	}
+}
+
+func TestCombineTrimmedShortShare(t *testing.T) {
+	shares := map[byte][]byte{1: {1, 2, 3}, 2: {1, 2}}
+
+	if _, err := CombineTrimmed(shares, 3); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func TestCombineAndWipe(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(3, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := CombineAndWipe(shares); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+
+	zero := make([]byte, len(secret))
+	for x, y := range shares {
+		if !bytes.Equal(y, zero) {
+			t.Errorf("Share %v was %v, but expected zeros", x, y)
+		}
+	}
+}
+
+func TestCombinePairMatchesInterpolate(t *testing.T) {
+	y1, y2 := make([]byte, 256), make([]byte, 256)
+	for i := range y1 {
+		y1[i], y2[i] = byte(i), byte(255-i)
+	}
+
+	for _, ids := range [][2]byte{{1, 2}, {1, 255}, {17, 200}} {
+		shares := map[byte][]byte{ids[0]: y1, ids[1]: y2}
+		secret := Combine(shares)
+
+		for i := range y1 {
+			points := []pair{{x: ids[0], y: y1[i]}, {x: ids[1], y: y2[i]}}
+			if v, want := secret[i], interpolate(points, 0); v != want {
+				t.Fatalf("Was %v, but expected %v", v, want)
+			}
+		}
+	}
+}
+
+func BenchmarkCombinePair(b *testing.B) {
+	shares, err := Split(5, 2, make([]byte, 64*1024))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(2)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(64 * 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Combine(subset)
+	}
+}
+
+func BenchmarkCombinePairInterpolate(b *testing.B) {
+	shares, err := Split(5, 2, make([]byte, 64*1024))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	points := []pair{{x: 1}, {x: 2}}
+	secret := make([]byte, 64*1024)
+
+	b.ReportAllocs()
+	b.SetBytes(64 * 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range secret {
+			points[0].y, points[1].y = shares[1][j], shares[2][j]
+			secret[j] = interpolate(points, 0)
+		}
+	}
+}
+
+func TestEveryByteValue(t *testing.T) {
+	// every byte value, in ascending, descending, and rotated order, so that
+	// each appears at several positions, including the first and last
+	var secrets [3][]byte
+	for i := range secrets {
+		secrets[i] = make([]byte, 256)
+	}
+	for i := 0; i < 256; i++ {
+		secrets[0][i] = byte(i)
+		secrets[1][i] = byte(255 - i)
+		secrets[2][i] = byte(i + 128)
+	}
+
+	splits := []struct {
+		name  string
+		split func(n, k byte, secret []byte) (map[byte][]byte, error)
+	}{
+		{"Split", Split},
+		{"SplitConcurrent", func(n, k byte, secret []byte) (map[byte][]byte, error) {
+			return SplitConcurrent(n, k, secret, 4)
+		}},
+		{"SplitN", func(n, k byte, secret []byte) (map[byte][]byte, error) {
+			dense, err := SplitN(n, k, secret)
+			if err != nil {
+				return nil, err
+			}
+
+			shares := make(map[byte][]byte, n)
+			for x := 1; x < len(dense); x++ {
+				shares[byte(x)] = dense[x]
+			}
+			return shares, nil
+		}},
+	}
+
+	for _, s := range splits {
+		for _, k := range []byte{2, 3, 5} {
+			for _, secret := range secrets {
+				shares, err := s.split(5, k, secret)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// every K-subset of the five shares
+				for mask := 0; mask < 1<<5; mask++ {
+					subset := make(map[byte][]byte, k)
+					dense := make([][]byte, 6)
+					for x := byte(1); x <= 5; x++ {
+						if mask&(1<<(x-1)) != 0 {
+							subset[x] = shares[x]
+							dense[x] = shares[x]
+						}
+					}
+
+					if len(subset) != int(k) {
+						continue
+					}
+
+					if v := Combine(subset); !bytes.Equal(v, secret) {
+						t.Errorf("%s with K=%v didn't recover the secret from %v", s.name, k, ShareSet(subset).IDs())
+					}
+
+					v, err := CombineN(dense, k)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if !bytes.Equal(v, secret) {
+						t.Errorf("%s with K=%v didn't recover the secret with CombineN", s.name, k)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCombineBestEffort(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// two shares are intact, but the other two are truncated at different
+	// points, leaving only the first 8 bytes covered by three shares
+	columns := map[byte][]byte{
+		1: shares[1],
+		2: shares[2],
+		3: shares[3][:8],
+		4: shares[4][:5],
+	}
+
+	v, recoverable := CombineBestEffort(columns, 3)
+	if len(v) != len(secret) || len(recoverable) != len(secret) {
+		t.Fatalf("Was %v and %v, but expected %v bytes", v, recoverable, len(secret))
+	}
+
+	for i := range secret {
+		if want := i < 8; recoverable[i] != want {
+			t.Errorf("Byte %v was recoverable=%v, but expected %v", i, recoverable[i], want)
+		}
+
+		if recoverable[i] && v[i] != secret[i] {
+			t.Errorf("Byte %v was %v, but expected %v", i, v[i], secret[i])
+		}
+
+		if !recoverable[i] && v[i] != 0 {
+			t.Errorf("Byte %v was %v, but expected 0", i, v[i])
+		}
+	}
+}
+
+func TestCombineUsing(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ids := range [][]byte{{1, 2, 3}, {5, 3, 1}, {2, 4, 5}, {1, 2, 3, 4, 5}} {
+		v, err := CombineUsing(shares, ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(v, secret) {
+			t.Errorf("IDs %v recovered %v, but expected %v", ids, v, secret)
+		}
+	}
+
+	// the untrusted shares are not used, so corrupting them changes nothing
+	shares[4] = []byte("not a real share!")
+	if v, err := CombineUsing(shares, []byte{1, 2, 3}); err != nil || !bytes.Equal(v, secret) {
+		t.Errorf("Was %v (%v), but expected %v", v, err, secret)
+	}
+}
+
+func TestCombineUsingInvalid(t *testing.T) {
+	shares := map[byte][]byte{1: {1}, 2: {2}, 3: {3, 4}}
+
+	for _, c := range []struct {
+		ids []byte
+		err error
+	}{
+		{[]byte{1}, ErrTooFewShares},
+		{[]byte{1, 4}, ErrTooFewShares},
+		{[]byte{0, 1}, ErrInvalidID},
+		{[]byte{1, 1}, ErrDuplicateID},
+		{[]byte{1, 3}, ErrInvalidLength},
+	} {
+		if _, err := CombineUsing(shares, c.ids); !errors.Is(err, c.err) {
+			t.Errorf("IDs %v: was %v, but expected %v", c.ids, err, c.err)
+		}
+	}
+}
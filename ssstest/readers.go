@@ -0,0 +1,55 @@
+package ssstest
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// ErrInjected is the error returned by a FailingReader once its bytes are
+// exhausted.
+var ErrInjected = errors.New("ssstest: injected read failure")
+
+// FailingReader returns a reader which reads from crypto/rand until n bytes
+// have been read, then fails with ErrInjected. It is intended for tests only,
+// to exercise the handling of failures to gather randomness, such as from
+// sss.SplitWithReader.
+func FailingReader(n int) io.Reader {
+	return &failingReader{remaining: n}
+}
+
+type failingReader struct {
+	remaining int
+}
+
+func (r *failingReader) Read(b []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, ErrInjected
+	}
+
+	if len(b) > r.remaining {
+		b = b[:r.remaining]
+	}
+
+	n, err := rand.Read(b)
+	r.remaining -= n
+	return n, err
+}
+
+// ZeroReader returns a reader which reads an endless stream of zeros. It is
+// intended for tests only, to exercise the handling of degenerate randomness:
+// splitting with K > 1 from a ZeroReader fails with
+// sss.ErrDegenerateRandomness, as the highest-degree coefficient of each
+// polynomial must be non-zero.
+func ZeroReader() io.Reader {
+	return zeroReader{}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
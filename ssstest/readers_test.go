@@ -0,0 +1,55 @@
+package ssstest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/codahale/sss"
+)
+
+func TestFailingReader(t *testing.T) {
+	r := FailingReader(10)
+
+	b := make([]byte, 16)
+	n, err := io.ReadFull(r, b)
+	if v, want := n, 10; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if err != ErrInjected {
+		t.Errorf("Was %v, but expected %v", err, ErrInjected)
+	}
+}
+
+func TestZeroReader(t *testing.T) {
+	b := []byte{1, 2, 3, 4}
+	if _, err := io.ReadFull(ZeroReader(), b); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := b, make([]byte, 4); !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestSplitWithReaderFailure(t *testing.T) {
+	// enough randomness for the first byte's polynomial, but not the second's
+	if _, err := sss.SplitWithReader(5, 3, []byte("hi"), FailingReader(3)); err != ErrInjected {
+		t.Errorf("Was %v, but expected %v", err, ErrInjected)
+	}
+}
+
+func TestSplitWithReaderZeros(t *testing.T) {
+	if _, err := sss.SplitWithReader(5, 3, []byte("hi"), ZeroReader()); err != sss.ErrDegenerateRandomness {
+		t.Errorf("Was %v, but expected %v", err, sss.ErrDegenerateRandomness)
+	}
+}
+
+func TestSplitWithReaderThresholdOfOne(t *testing.T) {
+	// a threshold of one is rejected before any randomness is read
+	if _, err := sss.SplitWithReader(5, 1, []byte("hi"), ZeroReader()); !errors.Is(err, sss.ErrInvalidThreshold) {
+		t.Errorf("Was %v, but expected %v", err, sss.ErrInvalidThreshold)
+	}
+}
@@ -0,0 +1,125 @@
+// Package ssstest provides utilities for testing code which uses package sss.
+package ssstest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/codahale/sss"
+)
+
+const (
+	// the most K-subsets which will be checked exhaustively
+	maxSubsets = 1024
+	// the number of subsets checked when there are too many to enumerate
+	sampledSubsets = 64
+	// the shortest secret for which K-1 shares recovering the secret is
+	// treated as a failure (the chance of it happening by accident is 2^-128)
+	minUnrecoverableLen = 16
+)
+
+// RoundtripCheck splits the given secret into N shares of which K are required
+// to recover it, and asserts that every K-subset of the shares recovers the
+// secret exactly. If there are more than 1024 such subsets, a random sample of
+// them is checked instead.
+//
+// For secrets of 16 bytes or more, it also asserts that a sample of (K-1)-
+// subsets do not recover the secret. Shorter secrets can be recovered from
+// K-1 shares by chance, so this check is skipped for them.
+func RoundtripCheck(t testing.TB, n, k byte, secret []byte) {
+	t.Helper()
+
+	shares, err := sss.Split(n, k, secret)
+	if err != nil {
+		t.Fatalf("Split(%d, %d) failed: %v", n, k, err)
+	}
+
+	checkShares(t, shares, k, secret)
+}
+
+// asserts that the shares recover the secret from K of them, and not from K-1
+func checkShares(t testing.TB, shares map[byte][]byte, k byte, secret []byte) {
+	t.Helper()
+
+	for _, subset := range subsets(t, shares, k) {
+		if v := sss.Combine(subset); !bytes.Equal(v, secret) {
+			t.Errorf("Shares %v recovered %x, but expected %x",
+				sss.ShareSet(subset).IDs(), v, secret)
+		}
+	}
+
+	if len(secret) < minUnrecoverableLen {
+		return
+	}
+
+	for i := 0; i < sampledSubsets; i++ {
+		subset := randomSubset(t, shares, k-1)
+		if v := sss.Combine(subset); bytes.Equal(v, secret) {
+			t.Errorf("Shares %v recovered the secret, but are fewer than K=%d",
+				sss.ShareSet(subset).IDs(), k)
+		}
+	}
+}
+
+// returns every K-subset of the shares, or a random sample if there are too
+// many
+func subsets(t testing.TB, shares map[byte][]byte, k byte) []map[byte][]byte {
+	if binomial(len(shares), int(k)) > maxSubsets {
+		result := make([]map[byte][]byte, sampledSubsets)
+		for i := range result {
+			result[i] = randomSubset(t, shares, k)
+		}
+		return result
+	}
+
+	var result []map[byte][]byte
+	ids := sss.ShareSet(shares).IDs()
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+
+	for {
+		subset := make(map[byte][]byte, k)
+		for _, i := range idx {
+			subset[ids[i]] = shares[ids[i]]
+		}
+		result = append(result, subset)
+
+		// advance to the next combination in lexicographic order
+		i := len(idx) - 1
+		for i >= 0 && idx[i] == len(ids)-len(idx)+i {
+			i--
+		}
+
+		if i < 0 {
+			return result
+		}
+
+		idx[i]++
+		for j := i + 1; j < len(idx); j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
+
+func randomSubset(t testing.TB, shares map[byte][]byte, k byte) map[byte][]byte {
+	subset, err := sss.ShareSet(shares).RandomSubset(k, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return subset
+}
+
+// returns n choose k, saturating at maxSubsets+1
+func binomial(n, k int) int {
+	r := 1
+	for i := 1; i <= k; i++ {
+		r = r * (n - k + i) / i
+		if r > maxSubsets {
+			return maxSubsets + 1
+		}
+	}
+	return r
+}
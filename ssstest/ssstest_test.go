@@ -0,0 +1,92 @@
+package ssstest
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/codahale/sss"
+)
+
+func TestRoundtripCheck(t *testing.T) {
+	RoundtripCheck(t, 5, 3, []byte("well hello there!"))
+}
+
+func TestRoundtripCheckShortSecret(t *testing.T) {
+	RoundtripCheck(t, 2, 2, []byte{42})
+}
+
+func TestRoundtripCheckSampled(t *testing.T) {
+	RoundtripCheck(t, 30, 10, []byte("well hello there!"))
+}
+
+// records the failures reported through it instead of failing the test
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed = true
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failed = true
+	runtime.Goexit()
+}
+
+// reports whether f reported a failure through the given recorder
+func fails(f func(t testing.TB)) bool {
+	r := new(recorder)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(r)
+	}()
+	<-done
+	return r.failed
+}
+
+func TestRoundtripCheckInvalidParams(t *testing.T) {
+	if !fails(func(t testing.TB) { RoundtripCheck(t, 3, 5, []byte("well hello there!")) }) {
+		t.Error("Invalid parameters passed the check")
+	}
+}
+
+func TestCheckSharesCorrupt(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := sss.Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fails(func(t testing.TB) { checkShares(t, shares, 3, secret) }) {
+		t.Fatal("Valid shares failed the check")
+	}
+
+	shares[2][4] ^= 1
+
+	if !fails(func(t testing.TB) { checkShares(t, shares, 3, secret) }) {
+		t.Error("Corrupt shares passed the check")
+	}
+}
+
+func TestSubsets(t *testing.T) {
+	shares := map[byte][]byte{1: nil, 2: nil, 3: nil, 4: nil, 5: nil}
+
+	if v, want := len(subsets(t, shares, 3)), 10; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestBinomial(t *testing.T) {
+	if v, want := binomial(5, 3), 10; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if v, want := binomial(255, 128), maxSubsets+1; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
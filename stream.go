@@ -0,0 +1,486 @@
+package sss
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+)
+
+const (
+	// the size of the plaintext blocks sealed by SealStream
+	streamBlockSize = 64 * 1024
+	// the size of the per-block AONT key and checksum
+	streamHashSize = sha256.Size
+)
+
+// SealStream reads a secret from src and writes one share stream to each of
+// the N writers in dst, of which K are required to recover the secret. dst[i]
+// receives the share with ID i+1, and the writers are always written to in
+// index order.
+//
+// The secret is processed in fixed-size blocks, so memory use is bounded
+// regardless of the secret's length. Each block is passed through an
+// all-or-nothing transform before being split: the block and a checksum are
+// encrypted with a random key, and that key is masked with a hash of the
+// ciphertext. Without every byte of the transformed block the key cannot be
+// recovered, and without the key the checksum cannot be verified, so
+// combining fewer than K shares, or a corrupted share, is detected by
+// OpenStream.
+//
+// Each share stream is a sequence of frames, each consisting of a 4-byte
+// big-endian length followed by that many bytes of share data. The stream is
+// terminated by a zero-length frame. The last block, which is shorter than
+// the rest and may be empty, is marked as final in its checksum, so a stream
+// cut short at a block boundary is detected by OpenStream.
+func SealStream(n, k byte, src io.Reader, dst []io.Writer) error {
+	if err := checkWriters(n, dst); err != nil {
+		return err
+	}
+
+	// each block's length is validated as it is split
+	if err := Validate(int(n), int(k), 0); err != nil {
+		return err
+	}
+
+	buf := make([]byte, streamBlockSize)
+	for index, final := uint64(0), false; !final; index++ {
+		l, err := io.ReadFull(src, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+
+		// a short block, even an empty one, ends the secret
+		final = l < streamBlockSize
+
+		pkg, err := aontPackage(index, final, buf[:l])
+		if err != nil {
+			return err
+		}
+
+		shares, err := Split(n, k, pkg)
+		if err != nil {
+			return err
+		}
+
+		for i, w := range dst {
+			if err := writeFrame(w, shares[byte(i+1)]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, w := range dst {
+		if err := writeFrame(w, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OpenStream combines the given share streams, as written by SealStream, and
+// writes the recovered secret to dst. Returns ErrIntegrity if the shares do
+// not recover the secret or the streams end before the final block, a
+// *ShareError if a share stream is malformed or its frames differ in length
+// from those of the other streams, or ErrTooFewShares if there are no share
+// streams.
+//
+// Blocks are written to dst as they are verified, so on error dst may have
+// received a prefix of the secret.
+func OpenStream(src map[byte]io.Reader, dst io.Writer) error {
+	if len(src) == 0 {
+		return ErrTooFewShares
+	}
+
+	shares := make(map[byte][]byte, len(src))
+	for index, final := uint64(0), false; ; index++ {
+		for id, r := range src {
+			frame, err := readFrame(r, streamBlockSize+2*streamHashSize)
+			if err != nil {
+				return &ShareError{ID: id, Reason: err.Error(), Err: err}
+			}
+			shares[id] = frame
+		}
+
+		l := commonLength(shares)
+		for _, id := range ShareSet(shares).IDs() {
+			if len(shares[id]) != l {
+				return &ShareError{
+					ID:     id,
+					Reason: fmt.Sprintf("frame %d differs in length from those of the other shares", index),
+					Err:    ErrInvalidLength,
+				}
+			}
+		}
+
+		if l == 0 {
+			// a stream cut at a block boundary ends without a final block
+			if !final {
+				return ErrIntegrity
+			}
+			return nil
+		}
+
+		if final {
+			return ErrIntegrity
+		}
+		final = l-2*streamHashSize < streamBlockSize
+
+		block, err := aontUnpackage(index, final, Combine(shares))
+		if err != nil {
+			return err
+		}
+
+		if _, err := dst.Write(block); err != nil {
+			return err
+		}
+	}
+}
+
+// BundleReport describes the share streams checked by ValidateBundle.
+type BundleReport struct {
+	Blocks  map[byte]int   // the number of blocks in each well-formed stream
+	Lengths map[byte]int64 // the number of share bytes in each well-formed stream
+	Errors  map[byte]error // the problem with each malformed stream
+}
+
+// ValidateBundle checks that share streams, as written by SealStream, are
+// well-formed and consistent before committing to a recovery with OpenStream,
+// then rewinds each stream to where it started. A stream is malformed if it
+// cannot be seeked, if its frames cannot be read to the terminating frame, or
+// if its frames differ in length from those of most well-formed streams (or,
+// on a tie, of the one with the lowest ID).
+//
+// Returns ErrTooFewShares if fewer than K streams are well-formed. Only the
+// framing is checked: whether the shares recover the secret is only known once
+// OpenStream has combined them.
+func ValidateBundle(srcs map[byte]io.ReadSeeker, k byte) (BundleReport, error) {
+	report := BundleReport{
+		Blocks:  make(map[byte]int, len(srcs)),
+		Lengths: make(map[byte]int64, len(srcs)),
+		Errors:  make(map[byte]error),
+	}
+
+	if err := checkThreshold(k); err != nil {
+		return report, err
+	}
+
+	frames := make(map[byte][]int, len(srcs))
+	for id, r := range srcs {
+		if err := readFrameLengths(r, frames, id); err != nil {
+			report.Errors[id] = &ShareError{ID: id, Reason: err.Error(), Err: err}
+		}
+	}
+
+	ids := make([]byte, 0, len(srcs))
+	for id := range srcs {
+		if _, ok := report.Errors[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	// the layout most streams agree on, so that one corrupt stream cannot
+	// cast doubt on the rest
+	var ref []int
+	best := 0
+	for _, a := range ids {
+		agree := 0
+		for _, b := range ids {
+			if slices.Equal(frames[a], frames[b]) {
+				agree++
+			}
+		}
+
+		if agree > best {
+			ref, best = frames[a], agree
+		}
+	}
+
+	for _, id := range ids {
+		if !slices.Equal(frames[id], ref) {
+			report.Errors[id] = &ShareError{
+				ID:     id,
+				Reason: "frames differ in length from those of most shares",
+				Err:    ErrInvalidLength,
+			}
+			continue
+		}
+
+		report.Blocks[id] = len(frames[id])
+		for _, l := range frames[id] {
+			report.Lengths[id] += int64(l)
+		}
+	}
+
+	if len(report.Blocks) < int(k) {
+		return report, ErrTooFewShares
+	}
+
+	return report, nil
+}
+
+// returns the length shared by the most frames, preferring that of the lowest
+// ID on a tie
+func commonLength(frames map[byte][]byte) int {
+	counts := make(map[int]int, len(frames))
+	for _, f := range frames {
+		counts[len(f)]++
+	}
+
+	l := -1
+	for _, id := range ShareSet(frames).IDs() {
+		if n := len(frames[id]); l < 0 || counts[n] > counts[l] {
+			l = n
+		}
+	}
+	return l
+}
+
+// records the lengths of the stream's frames up to the terminating frame, then
+// rewinds it to where it started
+func readFrameLengths(r io.ReadSeeker, frames map[byte][]int, id byte) error {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	for {
+		frame, err := readFrame(r, streamBlockSize+2*streamHashSize)
+		if err != nil {
+			_, _ = r.Seek(start, io.SeekStart)
+			return err
+		}
+
+		if len(frame) == 0 {
+			break
+		}
+		frames[id] = append(frames[id], len(frame))
+	}
+
+	_, err = r.Seek(start, io.SeekStart)
+	return err
+}
+
+// transform a block into an all-or-nothing package
+func aontPackage(index uint64, final bool, block []byte) ([]byte, error) {
+	key := make([]byte, streamHashSize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	pkg := make([]byte, len(block)+2*streamHashSize)
+	copy(pkg, block)
+	copy(pkg[len(block):], blockChecksum(index, final, block))
+
+	c := pkg[:len(block)+streamHashSize]
+	if err := aontXOR(key, c); err != nil {
+		return nil, err
+	}
+
+	h := sha256.Sum256(c)
+	for i := range key {
+		pkg[len(c)+i] = key[i] ^ h[i]
+	}
+
+	return pkg, nil
+}
+
+// recover a block from an all-or-nothing package
+func aontUnpackage(index uint64, final bool, pkg []byte) ([]byte, error) {
+	if len(pkg) < 2*streamHashSize {
+		return nil, ErrIntegrity
+	}
+
+	c := pkg[:len(pkg)-streamHashSize]
+	h := sha256.Sum256(c)
+	key := make([]byte, streamHashSize)
+	for i := range key {
+		key[i] = pkg[len(c)+i] ^ h[i]
+	}
+
+	if err := aontXOR(key, c); err != nil {
+		return nil, err
+	}
+
+	block := c[:len(c)-streamHashSize]
+	sum := blockChecksum(index, final, block)
+	if subtle.ConstantTimeCompare(sum, c[len(block):]) != 1 {
+		return nil, ErrIntegrity
+	}
+
+	return block, nil
+}
+
+// a checksum binding a block's contents to its position in the stream and to
+// whether it ends the stream
+func blockChecksum(index uint64, final bool, block []byte) []byte {
+	h := sha256.New()
+	_ = binary.Write(h, binary.BigEndian, index)
+	_ = binary.Write(h, binary.BigEndian, final)
+	_, _ = h.Write(block)
+	return h.Sum(nil)
+}
+
+// encrypt or decrypt b in place with AES-CTR under a single-use key
+func aontXOR(key, b []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	cipher.NewCTR(block, iv).XORKeyStream(b, b)
+	return nil
+}
+
+// returns an error unless there are N writers, none of them nil
+func checkWriters(n byte, dst []io.Writer) error {
+	if len(dst) != int(n) {
+		return fmt.Errorf("%w: %d writers given for %d shares", ErrInvalidCount, len(dst), n)
+	}
+
+	for i, w := range dst {
+		if w == nil {
+			return fmt.Errorf("%w: writer %d (share %d) is nil", ErrNilWriter, i, i+1)
+		}
+	}
+	return nil
+}
+
+// write a length-prefixed frame
+func writeFrame(w io.Writer, b []byte) error {
+	var h [4]byte
+	binary.BigEndian.PutUint32(h[:], uint32(len(b)))
+	if _, err := w.Write(h[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(b)
+	return err
+}
+
+// read a length-prefixed frame of at most limit bytes
+func readFrame(r io.Reader, limit int) ([]byte, error) {
+	var h [4]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	l := binary.BigEndian.Uint32(h[:])
+	if uint64(l) > uint64(limit) {
+		return nil, ErrInvalidLength
+	}
+
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SplitChanInput splits a secret arriving as a sequence of chunks on in into N
+// shares of which K are required to recover the secret, writing the share with
+// ID i+1 to out[i] until in is closed. Each byte of the secret is split
+// independently, so the shares are the same however the secret is chunked.
+//
+// Writes to out are buffered, and any partially filled buffers are flushed
+// once in is closed. The writers are always written to in index order, so
+// out[0], holding share 1, is written first, and so on. If an error occurs,
+// SplitChanInput returns without receiving the remaining chunks, so the sender
+// must not block indefinitely.
+func SplitChanInput(n, k byte, in <-chan []byte, out []io.Writer) error {
+	if err := checkWriters(n, out); err != nil {
+		return err
+	}
+
+	// each chunk's length is validated as it is split
+	if err := Validate(int(n), int(k), 0); err != nil {
+		return err
+	}
+
+	dst := make([]*bufio.Writer, n)
+	for i, w := range out {
+		dst[i] = bufio.NewWriter(w)
+	}
+
+	for chunk := range in {
+		shares, err := Split(n, k, chunk)
+		if err != nil {
+			return err
+		}
+
+		for i, w := range dst {
+			if _, err := w.Write(shares[byte(i+1)]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, w := range dst {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CombineReader returns a reader which recovers the original secret from the
+// given shares as it is read, interpolating only as many bytes as each Read
+// requests. The shares are validated on the first Read, which returns
+// ErrTooFewShares if there are none, ErrInvalidID if any has an ID of zero, or
+// ErrInvalidLength if they differ in length.
+func CombineReader(shares map[byte][]byte) io.Reader {
+	return &combineReader{shares: shares}
+}
+
+type combineReader struct {
+	shares map[byte][]byte
+	points []pair
+	offset int
+	length int
+	err    error
+}
+
+func (r *combineReader) Read(b []byte) (int, error) {
+	if r.points == nil && r.err == nil {
+		if r.err = checkShares(r.shares); r.err == nil {
+			r.points = sharePoints(r.shares)
+			r.length = len(r.shares[r.points[0].x])
+		}
+	}
+
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	if r.offset == r.length {
+		return 0, io.EOF
+	}
+
+	n := 0
+	for ; n < len(b) && r.offset < r.length; n++ {
+		for j := range r.points {
+			r.points[j].y = r.shares[r.points[j].x][r.offset]
+		}
+		b[n] = interpolate(r.points, 0)
+		r.offset++
+	}
+	return n, nil
+}
@@ -0,0 +1,503 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"testing/iotest"
+)
+
+func sealStream(t *testing.T, n, k byte, secret []byte) []*bytes.Buffer {
+	bufs := make([]*bytes.Buffer, n)
+	dst := make([]io.Writer, n)
+	for i := range bufs {
+		bufs[i] = new(bytes.Buffer)
+		dst[i] = bufs[i]
+	}
+
+	if err := SealStream(n, k, bytes.NewReader(secret), dst); err != nil {
+		t.Fatal(err)
+	}
+
+	return bufs
+}
+
+func TestSealStream(t *testing.T) {
+	secret := make([]byte, 2*streamBlockSize+100)
+	if _, err := rand.Read(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	bufs := sealStream(t, 5, 3, secret)
+
+	src := map[byte]io.Reader{
+		1: bufs[0],
+		3: bufs[2],
+		5: bufs[4],
+	}
+
+	out := new(bytes.Buffer)
+	if err := OpenStream(src, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(out.Bytes(), secret) {
+		t.Error("Recovered secret didn't match")
+	}
+}
+
+func TestSealStreamEmpty(t *testing.T) {
+	bufs := sealStream(t, 2, 2, nil)
+
+	out := new(bytes.Buffer)
+	if err := OpenStream(map[byte]io.Reader{1: bufs[0], 2: bufs[1]}, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Len() != 0 {
+		t.Errorf("Was %v, but expected an empty secret", out.Bytes())
+	}
+}
+
+func TestOpenStreamTooFewShares(t *testing.T) {
+	bufs := sealStream(t, 3, 3, []byte("well hello there!"))
+
+	src := map[byte]io.Reader{
+		1: bufs[0],
+		2: bufs[1],
+	}
+
+	if err := OpenStream(src, io.Discard); err != ErrIntegrity {
+		t.Errorf("Was %v, but expected %v", err, ErrIntegrity)
+	}
+}
+
+func TestOpenStreamNoShares(t *testing.T) {
+	if err := OpenStream(nil, io.Discard); err != ErrTooFewShares {
+		t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+	}
+}
+
+func TestSealStreamInvalidParams(t *testing.T) {
+	dst := []io.Writer{io.Discard, io.Discard, io.Discard}
+
+	if err := SealStream(3, 5, bytes.NewReader(nil), dst); err != ErrInvalidCount {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidCount)
+	}
+
+	if err := SealStream(0, 0, bytes.NewReader(nil), nil); !errors.Is(err, ErrInvalidThreshold) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidThreshold)
+	}
+}
+
+func TestOpenStreamCorrupted(t *testing.T) {
+	bufs := sealStream(t, 3, 2, []byte("well hello there!"))
+	bufs[1].Bytes()[10] ^= 1
+
+	src := map[byte]io.Reader{
+		1: bufs[0],
+		2: bufs[1],
+	}
+
+	if err := OpenStream(src, io.Discard); err != ErrIntegrity {
+		t.Errorf("Was %v, but expected %v", err, ErrIntegrity)
+	}
+}
+
+func TestOpenStreamTruncated(t *testing.T) {
+	bufs := sealStream(t, 2, 2, []byte("well hello there!"))
+
+	src := map[byte]io.Reader{
+		1: bytes.NewReader(bufs[0].Bytes()[:bufs[0].Len()-4]),
+		2: bytes.NewReader(bufs[1].Bytes()[:bufs[1].Len()-4]),
+	}
+
+	if err := OpenStream(src, io.Discard); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Was %v, but expected %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestOpenStreamShareError(t *testing.T) {
+	bufs := sealStream(t, 3, 2, []byte("well hello there!"))
+
+	src := map[byte]io.Reader{
+		1: bufs[0],
+		3: bytes.NewReader(bufs[2].Bytes()[:10]),
+	}
+
+	err := OpenStream(src, io.Discard)
+
+	var e *ShareError
+	if !errors.As(err, &e) {
+		t.Fatalf("Was %v, but expected a ShareError", err)
+	}
+
+	if v, want := e.ID, byte(3); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Was %v, but expected %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestOpenStreamTruncatedAtBlock(t *testing.T) {
+	for _, l := range []int{streamBlockSize + 10, 2 * streamBlockSize} {
+		bufs := sealStream(t, 2, 2, make([]byte, l))
+
+		// keep only the first block, followed by a terminating frame
+		n := 4 + streamBlockSize + 2*streamHashSize
+		src := make(map[byte]io.Reader, len(bufs))
+		for i, b := range bufs {
+			src[byte(i+1)] = bytes.NewReader(append(b.Bytes()[:n:n], 0, 0, 0, 0))
+		}
+
+		if err := OpenStream(src, io.Discard); err != ErrIntegrity {
+			t.Errorf("%v bytes: was %v, but expected %v", l, err, ErrIntegrity)
+		}
+	}
+}
+
+func TestOpenStreamMismatchedFrames(t *testing.T) {
+	a := sealStream(t, 3, 2, []byte("well hello there!"))
+	b := sealStream(t, 3, 2, []byte("hi"))
+
+	src := map[byte]io.Reader{
+		1: a[0],
+		2: a[1],
+		3: b[2],
+	}
+
+	err := OpenStream(src, io.Discard)
+
+	var e *ShareError
+	if !errors.As(err, &e) {
+		t.Fatalf("Was %v, but expected a ShareError", err)
+	}
+
+	if v, want := e.ID, byte(3); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func TestSplitChanInput(t *testing.T) {
+	secret := []byte("well hello there, how are you doing today?")
+
+	in := make(chan []byte)
+	go func() {
+		defer close(in)
+		for _, l := range []int{1, 0, 7, 13, 2, len(secret)} {
+			if l > len(secret) {
+				l = len(secret)
+			}
+			in <- secret[:l]
+			secret = secret[l:]
+		}
+	}()
+
+	bufs := make([]*bytes.Buffer, 4)
+	out := make([]io.Writer, 4)
+	for i := range bufs {
+		bufs[i] = new(bytes.Buffer)
+		out[i] = bufs[i]
+	}
+
+	if err := SplitChanInput(4, 3, in, out); err != nil {
+		t.Fatal(err)
+	}
+
+	shares := map[byte][]byte{
+		1: bufs[0].Bytes(),
+		2: bufs[1].Bytes(),
+		4: bufs[3].Bytes(),
+	}
+
+	want := []byte("well hello there, how are you doing today?")
+	if v := Combine(shares); !bytes.Equal(v, want) {
+		t.Errorf("Was %q, but expected %q", v, want)
+	}
+}
+
+func TestSplitChanInputWriterCount(t *testing.T) {
+	in := make(chan []byte)
+	close(in)
+
+	if err := SplitChanInput(3, 2, in, []io.Writer{io.Discard}); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidCount)
+	}
+}
+
+func TestCombineReader(t *testing.T) {
+	secret := make([]byte, 1000)
+	if _, err := rand.Read(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// read in small, uneven chunks
+	out, err := io.ReadAll(iotest.HalfReader(CombineReader(subset)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := out, Combine(subset); !bytes.Equal(v, want) {
+		t.Error("CombineReader didn't match Combine")
+	}
+}
+
+func TestCombineReaderMismatchedLength(t *testing.T) {
+	r := CombineReader(map[byte][]byte{1: {1, 2}, 2: {3}})
+
+	if _, err := r.Read(make([]byte, 1)); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+
+	if _, err := r.Read(make([]byte, 1)); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func TestCombineReaderEmptySecret(t *testing.T) {
+	r := CombineReader(map[byte][]byte{1: {}, 2: {}})
+
+	if _, err := r.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Was %v, but expected %v", err, io.EOF)
+	}
+}
+
+func TestNilWriter(t *testing.T) {
+	dst := []io.Writer{io.Discard, nil, io.Discard}
+
+	err := SealStream(3, 2, bytes.NewReader([]byte("secret")), dst)
+	if !errors.Is(err, ErrNilWriter) {
+		t.Errorf("Was %v, but expected %v", err, ErrNilWriter)
+	}
+
+	in := make(chan []byte)
+	close(in)
+
+	if err := SplitChanInput(3, 2, in, dst); !errors.Is(err, ErrNilWriter) {
+		t.Errorf("Was %v, but expected %v", err, ErrNilWriter)
+	}
+}
+
+// records the index of each writer in the order they are written to
+type orderWriter struct {
+	index int
+	order *[]int
+	bytes.Buffer
+}
+
+func (w *orderWriter) Write(b []byte) (int, error) {
+	*w.order = append(*w.order, w.index)
+	return w.Buffer.Write(b)
+}
+
+func TestSplitChanInputOrder(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	for run := 0; run < 5; run++ {
+		in := make(chan []byte, 2)
+		in <- secret[:5]
+		in <- secret[5:]
+		close(in)
+
+		var order []int
+		bufs := make([]*orderWriter, 4)
+		out := make([]io.Writer, 4)
+		for i := range bufs {
+			bufs[i] = &orderWriter{index: i, order: &order}
+			out[i] = bufs[i]
+		}
+
+		if err := SplitChanInput(4, 2, in, out); err != nil {
+			t.Fatal(err)
+		}
+
+		if v, want := order, []int{0, 1, 2, 3}; !slices.Equal(v, want) {
+			t.Errorf("Was %v, but expected %v", v, want)
+		}
+
+		// every pair recovers the secret only if out[i] holds share i+1
+		for i := range bufs {
+			for j := i + 1; j < len(bufs); j++ {
+				shares := map[byte][]byte{
+					byte(i + 1): bufs[i].Bytes(),
+					byte(j + 1): bufs[j].Bytes(),
+				}
+
+				if v := Combine(shares); !bytes.Equal(v, secret) {
+					t.Errorf("Writers %v and %v recovered %v, but expected %v", i, j, v, secret)
+				}
+			}
+		}
+	}
+}
+
+func TestValidateBundle(t *testing.T) {
+	secret := make([]byte, streamBlockSize+100)
+	if _, err := rand.Read(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	bufs := sealStream(t, 4, 2, secret)
+
+	// share 3 is truncated, and share 4 has a corrupt frame length
+	short := bufs[2].Bytes()[:bufs[2].Len()-10]
+	bad := append([]byte(nil), bufs[3].Bytes()...)
+	bad[0] = 0xff
+
+	srcs := map[byte]io.ReadSeeker{
+		1: bytes.NewReader(bufs[0].Bytes()),
+		2: bytes.NewReader(bufs[1].Bytes()),
+		3: bytes.NewReader(short),
+		4: bytes.NewReader(bad),
+	}
+
+	report, err := ValidateBundle(srcs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []byte{1, 2} {
+		if v, want := report.Blocks[id], 2; v != want {
+			t.Errorf("Share %v was %v blocks, but expected %v", id, v, want)
+		}
+
+		if v, want := report.Lengths[id], int64(len(secret)+4*streamHashSize); v != want {
+			t.Errorf("Share %v was %v bytes, but expected %v", id, v, want)
+		}
+	}
+
+	if err := report.Errors[3]; !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Was %v, but expected %v", err, io.ErrUnexpectedEOF)
+	}
+
+	if err := report.Errors[4]; !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+
+	// the streams are rewound, so the valid ones can still be opened
+	out := new(bytes.Buffer)
+	src := map[byte]io.Reader{1: srcs[1], 2: srcs[2]}
+	if err := OpenStream(src, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(out.Bytes(), secret) {
+		t.Error("Recovered secret didn't match")
+	}
+}
+
+func TestValidateBundleTooFewShares(t *testing.T) {
+	bufs := sealStream(t, 3, 2, []byte("well hello there!"))
+
+	srcs := map[byte]io.ReadSeeker{
+		1: bytes.NewReader(bufs[0].Bytes()),
+		2: bytes.NewReader(bufs[1].Bytes()[:10]),
+	}
+
+	if _, err := ValidateBundle(srcs, 2); err != ErrTooFewShares {
+		t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+	}
+}
+
+func TestValidateBundleMismatched(t *testing.T) {
+	a := sealStream(t, 2, 2, []byte("well hello there!"))
+	b := sealStream(t, 2, 2, []byte("hi"))
+
+	srcs := map[byte]io.ReadSeeker{
+		1: bytes.NewReader(a[0].Bytes()),
+		2: bytes.NewReader(b[1].Bytes()),
+	}
+
+	report, err := ValidateBundle(srcs, 2)
+	if err != ErrTooFewShares {
+		t.Errorf("Was %v, but expected %v", err, ErrTooFewShares)
+	}
+
+	if err := report.Errors[2]; !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}
+
+func TestValidateBundleLowestIDCorrupt(t *testing.T) {
+	secret := make([]byte, streamBlockSize+100)
+	bufs := sealStream(t, 3, 2, secret)
+	other := sealStream(t, 3, 2, []byte("hi"))
+
+	srcs := map[byte]io.ReadSeeker{
+		1: bytes.NewReader(other[0].Bytes()),
+		2: bytes.NewReader(bufs[1].Bytes()),
+		3: bytes.NewReader(bufs[2].Bytes()),
+	}
+
+	report, err := ValidateBundle(srcs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := report.Errors[1]; !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+
+	for _, id := range []byte{2, 3} {
+		if err := report.Errors[id]; err != nil {
+			t.Errorf("Share %v was %v, but expected no error", id, err)
+		}
+	}
+}
+
+// a stream which cannot be seeked
+type unseekable struct {
+	io.Reader
+}
+
+func (unseekable) Seek(int64, int) (int64, error) {
+	return 0, errors.ErrUnsupported
+}
+
+func TestValidateBundleSeekError(t *testing.T) {
+	bufs := sealStream(t, 3, 2, []byte("well hello there!"))
+
+	srcs := map[byte]io.ReadSeeker{
+		1: unseekable{bufs[0]},
+		2: bytes.NewReader(bufs[1].Bytes()),
+		3: bytes.NewReader(bufs[2].Bytes()),
+	}
+
+	report, err := ValidateBundle(srcs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := report.Errors[1]; !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Was %v, but expected %v", err, errors.ErrUnsupported)
+	}
+
+	// the other streams are still rewound
+	out := new(bytes.Buffer)
+	if err := OpenStream(map[byte]io.Reader{2: srcs[2], 3: srcs[3]}, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := out.String(), "well hello there!"; v != want {
+		t.Errorf("Was %q, but expected %q", v, want)
+	}
+}
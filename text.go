@@ -0,0 +1,103 @@
+package sss
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// CombineHexMap combines shares encoded as a map of decimal share IDs to
+// hex-encoded shares, such as {"1": "a1b2c3", "2": "d4e5f6"}, into the original
+// secret.
+//
+// Returns an error wrapping ErrInvalidID if a key is not an integer in the
+// range 1-255, ErrDuplicateID if two keys denote the same ID (e.g. "1" and
+// "01"), ErrMalformedShare if a value is not valid hex, and
+// ErrInvalidLength if the shares differ in length.
+func CombineHexMap(m map[string]string) ([]byte, error) {
+	shares := make(map[byte][]byte, len(m))
+	for k, v := range m {
+		id, err := strconv.ParseUint(k, 10, 8)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("%w: %q is not in the range 1-255", ErrInvalidID, k)
+		}
+
+		if _, ok := shares[byte(id)]; ok {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateID, id)
+		}
+
+		y, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: share %d: %v", ErrMalformedShare, id, err)
+		}
+
+		shares[byte(id)] = y
+	}
+
+	return CombineChecked(shares)
+}
+
+// ShareInput is a share as submitted by a form, with an integer ID and a
+// base64-encoded share.
+type ShareInput struct {
+	ID   int
+	Data string
+}
+
+// RowError is returned when a particular ShareInput is invalid.
+type RowError struct {
+	Row   int    // the index of the invalid input
+	Field string // the name of the invalid field, "ID" or "Data"
+	Err   error  // the underlying error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %s: %v", e.Row, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// CombineFromPairs combines the given shares, encoded as base64 (with standard
+// padding), into the original secret.
+//
+// Returns a *RowError identifying the first invalid input: its underlying
+// error is ErrInvalidID if its ID is not in the range 1-255, ErrDuplicateID if
+// an earlier input has the same ID, ErrMalformedShare if its data is not valid
+// base64, or ErrInvalidLength if its decoded length differs from the first
+// input's.
+func CombineFromPairs(inputs []ShareInput) ([]byte, error) {
+	if len(inputs) == 0 {
+		return nil, ErrTooFewShares
+	}
+
+	shares := make(map[byte][]byte, len(inputs))
+	l := -1
+	for i, in := range inputs {
+		if in.ID < 1 || in.ID > 255 {
+			return nil, &RowError{Row: i, Field: "ID", Err: ErrInvalidID}
+		}
+
+		if _, ok := shares[byte(in.ID)]; ok {
+			return nil, &RowError{Row: i, Field: "ID", Err: ErrDuplicateID}
+		}
+
+		y, err := base64.StdEncoding.DecodeString(in.Data)
+		if err != nil {
+			return nil, &RowError{Row: i, Field: "Data",
+				Err: fmt.Errorf("%w: %v", ErrMalformedShare, err)}
+		}
+
+		if l >= 0 && len(y) != l {
+			return nil, &RowError{Row: i, Field: "Data", Err: ErrInvalidLength}
+		}
+		l = len(y)
+
+		shares[byte(in.ID)] = y
+	}
+
+	return Combine(shares), nil
+}
@@ -0,0 +1,109 @@
+package sss
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestCombineHexMap(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := make(map[string]string)
+	for _, id := range []byte{1, 2, 5} {
+		m[strconv.Itoa(int(id))] = hex.EncodeToString(shares[id])
+	}
+
+	actual, err := CombineHexMap(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Errorf("Was %v, but expected %v", actual, secret)
+	}
+}
+
+func TestCombineHexMapErrors(t *testing.T) {
+	for _, c := range []struct {
+		m   map[string]string
+		err error
+	}{
+		{map[string]string{"0": "aa", "1": "bb"}, ErrInvalidID},
+		{map[string]string{"256": "aa", "1": "bb"}, ErrInvalidID},
+		{map[string]string{"one": "aa", "2": "bb"}, ErrInvalidID},
+		{map[string]string{"1": "aa", "01": "bb"}, ErrDuplicateID},
+		{map[string]string{"1": "zz", "2": "bb"}, ErrMalformedShare},
+		{map[string]string{"1": "aa", "2": "bbcc"}, ErrInvalidLength},
+	} {
+		if _, err := CombineHexMap(c.m); !errors.Is(err, c.err) {
+			t.Errorf("%v: was %v, but expected %v", c.m, err, c.err)
+		}
+	}
+}
+
+func TestCombineFromPairs(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inputs []ShareInput
+	for _, id := range []byte{2, 3, 5} {
+		inputs = append(inputs, ShareInput{
+			ID:   int(id),
+			Data: base64.StdEncoding.EncodeToString(shares[id]),
+		})
+	}
+
+	actual, err := CombineFromPairs(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Errorf("Was %v, but expected %v", actual, secret)
+	}
+}
+
+func TestCombineFromPairsErrors(t *testing.T) {
+	ok := base64.StdEncoding.EncodeToString([]byte{1, 2})
+
+	for _, c := range []struct {
+		input ShareInput
+		field string
+		err   error
+	}{
+		{ShareInput{ID: 0, Data: ok}, "ID", ErrInvalidID},
+		{ShareInput{ID: 256, Data: ok}, "ID", ErrInvalidID},
+		{ShareInput{ID: 1, Data: ok}, "ID", ErrDuplicateID},
+		{ShareInput{ID: 3, Data: "not base64!"}, "Data", ErrMalformedShare},
+		{ShareInput{ID: 3, Data: "AQ=="}, "Data", ErrInvalidLength},
+	} {
+		_, err := CombineFromPairs([]ShareInput{{ID: 1, Data: ok}, c.input})
+
+		var e *RowError
+		if !errors.As(err, &e) {
+			t.Errorf("%v: was %v, but expected a RowError", c.input, err)
+			continue
+		}
+
+		if e.Row != 1 || e.Field != c.field {
+			t.Errorf("%v: was row %v %v, but expected row 1 %v", c.input, e.Row, e.Field, c.field)
+		}
+
+		if !errors.Is(err, c.err) {
+			t.Errorf("%v: was %v, but expected %v", c.input, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package sss
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+)
+
+// the size of the length field and checksum which precede a padded secret
+const uniformHeaderSize = 4 + 4
+
+// SplitUniformBlocks splits the given secret into N shares of which K are
+// required to recover the secret, as with Split, but first pads it so that
+// every share is a whole number of blocks of the given size. Secrets whose
+// lengths round up to the same number of blocks produce shares of the same
+// size, so an observer of the shares' sizes learns only that rounded length.
+//
+// The secret is prefixed with its length as a big-endian uint32 and a
+// big-endian CRC-32 (IEEE) of the length and the secret, and followed by zeros
+// up to the block boundary. The length, checksum, and padding are all split
+// along with the secret, so none of them is visible in the shares. Use
+// CombineUniformBlocks to recover the secret.
+func SplitUniformBlocks(n, k byte, secret []byte, block int) (map[byte][]byte, error) {
+	if block <= 0 || uint64(len(secret)) > math.MaxUint32 {
+		return nil, ErrInvalidLength
+	}
+
+	// round up without overflowing when block is close to math.MaxInt
+	l := uniformHeaderSize + len(secret)
+	pad := (block - l%block) % block
+	if l > math.MaxInt-pad {
+		return nil, ErrInvalidLength
+	}
+
+	if err := Validate(int(n), int(k), l+pad); err != nil {
+		return nil, err
+	}
+
+	padded := make([]byte, l+pad)
+	binary.BigEndian.PutUint32(padded, uint32(len(secret)))
+	copy(padded[uniformHeaderSize:], secret)
+	binary.BigEndian.PutUint32(padded[4:], uniformChecksum(padded[:4], secret))
+
+	shares, err := Split(n, k, padded)
+
+	// don't leave a copy of the secret lying around
+	for i := range padded {
+		padded[i] = 0
+	}
+
+	return shares, err
+}
+
+// CombineUniformBlocks combines shares split with SplitUniformBlocks into the
+// original secret, removing the padding. Returns ErrIntegrity if the recovered
+// length field is out of range, the length and secret fail their checksum, or
+// the padding is not all zeros, which happens if fewer than K shares, or a
+// corrupted share, were combined, and otherwise validates the shares as
+// CombineChecked does.
+//
+// N.B.: The checksum and padding checks are consistency checks only. They do
+// not prevent a malicious party from altering the recovered secret.
+func CombineUniformBlocks(shares map[byte][]byte) ([]byte, error) {
+	padded, err := CombineChecked(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(padded) < uniformHeaderSize {
+		return nil, ErrIntegrity
+	}
+
+	l := binary.BigEndian.Uint32(padded)
+	body := padded[uniformHeaderSize:]
+	if uint64(l) > uint64(len(body)) {
+		return nil, ErrIntegrity
+	}
+
+	if uniformChecksum(padded[:4], body[:l]) != binary.BigEndian.Uint32(padded[4:]) {
+		return nil, ErrIntegrity
+	}
+
+	for _, b := range body[l:] {
+		if b != 0 {
+			return nil, ErrIntegrity
+		}
+	}
+
+	return body[:l], nil
+}
+
+// a checksum of a padded secret's length field and the secret itself
+func uniformChecksum(length, secret []byte) uint32 {
+	return crc32.Update(crc32.ChecksumIEEE(length), crc32.IEEETable, secret)
+}
@@ -0,0 +1,99 @@
+package sss
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestSplitUniformBlocks(t *testing.T) {
+	for _, secret := range [][]byte{
+		{},
+		[]byte("hi"),
+		[]byte("well hello there!"),
+		bytes.Repeat([]byte{0xff}, 56),
+	} {
+		shares, err := SplitUniformBlocks(5, 3, secret, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for id, y := range shares {
+			if v, want := len(y), 64; v != want {
+				t.Errorf("Share %v was %v bytes, but expected %v", id, v, want)
+			}
+		}
+
+		subset, err := ShareSet(shares).Subset(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v, err := CombineUniformBlocks(subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(v, secret) {
+			t.Errorf("Was %v, but expected %v", v, secret)
+		}
+	}
+}
+
+func TestSplitUniformBlocksRoundsUp(t *testing.T) {
+	shares, err := SplitUniformBlocks(3, 2, make([]byte, 57), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := len(shares[1]), 128; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestSplitUniformBlocksInvalidBlock(t *testing.T) {
+	for _, block := range []int{0, math.MaxInt, math.MaxInt - 5} {
+		if _, err := SplitUniformBlocks(3, 2, []byte("hi"), block); err != ErrInvalidLength {
+			t.Errorf("%v: was %v, but expected %v", block, err, ErrInvalidLength)
+		}
+	}
+}
+
+func TestCombineUniformBlocksTooFewShares(t *testing.T) {
+	shares, err := SplitUniformBlocks(5, 3, []byte("well hello there!"), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the recovered padding is random, so is all but certain not to be zeros
+	if _, err := CombineUniformBlocks(subset); err != ErrIntegrity {
+		t.Errorf("Was %v, but expected %v", err, ErrIntegrity)
+	}
+}
+
+func TestCombineUniformBlocksCorruptLength(t *testing.T) {
+	shares, err := SplitUniformBlocks(5, 3, []byte("well hello there!"), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// altering the same byte of every share alters the recovered byte the same
+	// way, turning the length of 17 into 19, which still lies in the padding
+	for id := range subset {
+		subset[id][3] ^= 0x02
+	}
+
+	if _, err := CombineUniformBlocks(subset); err != ErrIntegrity {
+		t.Errorf("Was %v, but expected %v", err, ErrIntegrity)
+	}
+}
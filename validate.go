@@ -0,0 +1,33 @@
+package sss
+
+import (
+	"math"
+)
+
+// Validate checks the parameters of a split of a secret of the given length
+// into N shares of which K are required to recover it, returning the error the
+// split would. Returns an error wrapping ErrInvalidThreshold if K is not
+// between 2 and 255, ErrInvalidCount if N is not between K and 255, the
+// largest number of share IDs GF(2^8) provides, and ErrInvalidLength if the
+// secret's length is negative or N shares of it could not be allocated.
+//
+// Every function which splits a secret validates its parameters this way.
+func Validate(n, k, secretLen int) error {
+	if k < 0 || k > math.MaxUint8 {
+		return ErrInvalidThreshold
+	}
+
+	if err := checkThreshold(byte(k)); err != nil {
+		return err
+	}
+
+	if n < k || n > math.MaxUint8 {
+		return ErrInvalidCount
+	}
+
+	if secretLen < 0 || secretLen > math.MaxInt/n {
+		return ErrInvalidLength
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package sss
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, c := range []struct {
+		n, k, secretLen int
+		err             error
+	}{
+		{5, 3, 100, nil},
+		{255, 255, 0, nil},
+		{5, 0, 100, ErrZeroThreshold},
+		{5, 1, 100, ErrThresholdOfOne},
+		{5, -1, 100, ErrInvalidThreshold},
+		{300, 256, 100, ErrInvalidThreshold},
+		{2, 3, 100, ErrInvalidCount},
+		{256, 3, 100, ErrInvalidCount},
+		{5, 3, -1, ErrInvalidLength},
+		{5, 3, math.MaxInt / 4, ErrInvalidLength},
+	} {
+		if err := Validate(c.n, c.k, c.secretLen); !errors.Is(err, c.err) || (err == nil) != (c.err == nil) {
+			t.Errorf("Validate(%v, %v, %v) was %v, but expected %v", c.n, c.k, c.secretLen, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package sss
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// SplitValue encodes the given value with encoding/gob and splits the encoding
+// into N shares of which K are required to recover it, as with Split. Use
+// CombineValue to recover the value.
+//
+// The split payload includes gob's description of the value's type as well as
+// the value itself, so the shares are longer than the value's data alone, and
+// their length reveals something about the type.
+func SplitValue(n, k byte, v interface{}) (map[byte][]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	shares, err := Split(n, k, buf.Bytes())
+
+	// don't leave a copy of the encoded value lying around
+	b := buf.Bytes()
+	for i := range b {
+		b[i] = 0
+	}
+
+	return shares, err
+}
+
+// CombineValue combines shares split with SplitValue and decodes the recovered
+// value into v, which must be a pointer, as with gob.Decoder.Decode. Returns
+// the decoding error if the shares do not recover a valid encoding, which
+// happens if fewer than K shares, or a corrupted share, were combined, and
+// otherwise validates the shares as CombineChecked does.
+//
+// N.B.: Decoding is a consistency check only. It does not prevent a malicious
+// party from altering the recovered value.
+func CombineValue(shares map[byte][]byte, v interface{}) error {
+	b, err := CombineChecked(shares)
+	if err != nil {
+		return err
+	}
+
+	return gob.NewDecoder(bytes.NewReader(b)).Decode(v)
+}
@@ -0,0 +1,65 @@
+package sss
+
+import (
+	"reflect"
+	"testing"
+)
+
+type credentials struct {
+	Name  string
+	Keys  map[string][]byte
+	Owner struct {
+		Email string
+		Roles []string
+	}
+}
+
+func TestSplitValue(t *testing.T) {
+	var in credentials
+	in.Name = "production"
+	in.Keys = map[string][]byte{"signing": {1, 2, 3}, "backup": {4, 5}}
+	in.Owner.Email = "ops@example.com"
+	in.Owner.Roles = []string{"admin", "auditor"}
+
+	shares, err := SplitValue(5, 3, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out credentials
+	if err := CombineValue(subset, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("Was %+v, but expected %+v", out, in)
+	}
+}
+
+func TestSplitValueUnencodable(t *testing.T) {
+	if _, err := SplitValue(5, 3, func() {}); err == nil {
+		t.Error("Was nil, but expected an error")
+	}
+}
+
+func TestCombineValueTooFewShares(t *testing.T) {
+	shares, err := SplitValue(5, 3, "well hello there!")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out string
+	if err := CombineValue(subset, &out); err == nil && out == "well hello there!" {
+		t.Error("Recovered the value from too few shares")
+	}
+}
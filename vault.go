@@ -0,0 +1,45 @@
+package sss
+
+// ToVaultShares converts shares to the layout used by HashiCorp Vault's shamir
+// package, in which each share is its value followed by a single byte holding
+// its ID (the x-coordinate). Returns the shares in order of ID.
+//
+// Vault's shamir package performs its arithmetic in GF(2^8) with the same
+// reducing polynomial (x^8 + x^4 + x^3 + x + 1) as this package, and recovers
+// the secret by Lagrange interpolation at zero, so shares converted with
+// ToVaultShares can be combined by Vault and vice versa. The only difference is
+// that Vault assigns IDs at random rather than in sequence, which does not
+// affect recovery.
+func ToVaultShares(shares map[byte][]byte) [][]byte {
+	points := sharePoints(shares)
+
+	parts := make([][]byte, len(points))
+	for i, p := range points {
+		parts[i] = append(append(make([]byte, 0, len(shares[p.x])+1), shares[p.x]...), p.x)
+	}
+	return parts
+}
+
+// FromVaultShares converts shares in the layout used by HashiCorp Vault's
+// shamir package, as described by ToVaultShares, to a map of share IDs to
+// shares.
+func FromVaultShares(parts [][]byte) (map[byte][]byte, error) {
+	shares := make(map[byte][]byte, len(parts))
+	for _, part := range parts {
+		if len(part) < 1 || len(part) != len(parts[0]) {
+			return nil, ErrInvalidLength
+		}
+
+		x := part[len(part)-1]
+		if x == 0 {
+			return nil, ErrInvalidID
+		}
+
+		if _, ok := shares[x]; ok {
+			return nil, ErrDuplicateID
+		}
+
+		shares[x] = part[:len(part)-1]
+	}
+	return shares, nil
+}
@@ -0,0 +1,88 @@
+package sss
+
+import (
+	"bytes"
+	"testing"
+)
+
+// multiplication in GF(2^8) as implemented by Vault's shamir package
+func vaultMult(a, b byte) byte {
+	var r byte
+	for i := 8; i > 0; i-- {
+		r = (-(b >> byte(i-1) & 1) & a) ^ (-(r >> 7) & 0x1b) ^ (r + r)
+	}
+	return r
+}
+
+func TestVaultField(t *testing.T) {
+	for a := 0; a < fieldSize; a++ {
+		for b := 0; b < fieldSize; b++ {
+			if v, want := mul(byte(a), byte(b)), vaultMult(byte(a), byte(b)); v != want {
+				t.Fatalf("%v*%v was %v, but Vault computes %v", a, b, v, want)
+			}
+		}
+	}
+}
+
+func TestVaultShares(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := ToVaultShares(shares)
+	if v, want := parts[2], append(append([]byte(nil), shares[3]...), 3); !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	actual, err := FromVaultShares(parts[1:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := Combine(actual); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestFromVaultSharesVector(t *testing.T) {
+	// produced by Split in Vault 1.10.0's shamir package, with 5 parts of which
+	// 3 are required
+	parts := [][]byte{
+		{0x5e, 0x63, 0x62, 0xe1, 0xab, 0xee, 0x5a, 0xcd, 0x8f, 0xbc, 0xd6, 0x71, 0xb1, 0x00, 0xf0, 0x7e, 0xd3, 0x48},
+		{0xc5, 0xba, 0xcc, 0x65, 0x92, 0x9f, 0x2f, 0x6c, 0x40, 0x24, 0x9c, 0xdc, 0xf2, 0x1b, 0x30, 0x11, 0x8e, 0xe0},
+		{0x05, 0x10, 0x1c, 0xbe, 0x0b, 0x12, 0x09, 0x04, 0x85, 0x0c, 0x86, 0x05, 0x8d, 0xd8, 0x11, 0xc9, 0x8b, 0x89},
+		{0x2a, 0x2e, 0xe9, 0x07, 0xfd, 0x92, 0x94, 0xc9, 0x83, 0xa6, 0x79, 0xb0, 0x6a, 0x72, 0xe5, 0x64, 0xaa, 0xc2},
+		{0xad, 0xaf, 0xe4, 0xe0, 0x74, 0xc0, 0x01, 0x4d, 0x3e, 0x83, 0x8f, 0x0b, 0xe6, 0x9a, 0xea, 0x6d, 0x09, 0x3b},
+	}
+	secret := []byte("well hello there!")
+
+	for i := 0; i+3 <= len(parts); i++ {
+		shares, err := FromVaultShares(parts[i : i+3])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v := Combine(shares); !bytes.Equal(v, secret) {
+			t.Errorf("Was %v, but expected %v", v, secret)
+		}
+	}
+}
+
+func TestFromVaultSharesErrors(t *testing.T) {
+	for _, c := range []struct {
+		parts [][]byte
+		err   error
+	}{
+		{[][]byte{{1, 2}, {1}}, ErrInvalidLength},
+		{[][]byte{{}}, ErrInvalidLength},
+		{[][]byte{{1, 0}}, ErrInvalidID},
+		{[][]byte{{1, 2}, {3, 2}}, ErrDuplicateID},
+	} {
+		if _, err := FromVaultShares(c.parts); err != c.err {
+			t.Errorf("Was %v, but expected %v", err, c.err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package sss
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+)
+
+// QuickVerify checks, without combining the whole secret, that the given shares
+// lie on the same polynomials. It reconstructs each sampled byte position from
+// the K shares with the lowest IDs and checks that every other share agrees
+// with the result, examining the given number of randomly chosen positions.
+// Returns false if any share disagrees or the shares differ in length, and an
+// error if K is invalid or the source of randomness fails.
+//
+// The check is probabilistic. If a fraction f of a share's bytes are corrupt,
+// the probability that QuickVerify fails to notice is (1-f)^samples; a share
+// with a single corrupt byte out of L is likely to go unnoticed unless samples
+// is a sizeable fraction of L. With K or fewer shares there is nothing to
+// check the reconstruction against, and QuickVerify always returns true.
+func QuickVerify(shares map[byte][]byte, k byte, samples int, rand io.Reader) (bool, error) {
+	if err := checkThreshold(k); err != nil {
+		return false, err
+	}
+
+	points := sharePoints(shares)
+	if len(points) <= int(k) {
+		return true, nil
+	}
+
+	l := len(shares[points[0].x])
+	for _, y := range shares {
+		if len(y) != l {
+			return false, nil
+		}
+	}
+
+	if l == 0 {
+		return true, nil
+	}
+
+	basis, extra := points[:k], points[k:]
+	buf := make([]byte, 8)
+	for s := 0; s < samples; s++ {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return false, err
+		}
+
+		// the modulo bias is at most l/2^64, which is negligible
+		i := int(binary.BigEndian.Uint64(buf) % uint64(l))
+
+		for j := range basis {
+			basis[j].y = shares[basis[j].x][i]
+		}
+
+		for _, p := range extra {
+			if interpolate(basis, p.x) != shares[p.x][i] {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// VerifyRecovers combines the given shares and reports whether they recover
+// the expected secret, along with the bytes they do recover. The comparison
+// takes constant time with respect to the contents of the secret, so it does
+// not reveal how many bytes matched; it does reveal whether the lengths
+// differ.
+func VerifyRecovers(shares map[byte][]byte, expected []byte) (bool, []byte) {
+	recovered := Combine(shares)
+	return subtle.ConstantTimeCompare(recovered, expected) == 1, recovered
+}
+
+// SplitVerified splits the given secret into N shares of which K are required
+// to recover the secret, as with Split, then combines a random K-subset of the
+// shares and checks that it recovers the secret before returning them. Returns
+// ErrIntegrity if it does not, which indicates a fault such as memory
+// corruption, in which case no shares are returned.
+//
+// The check costs one extra combine, and catches catastrophic failures before
+// shares are distributed; it cannot detect shares altered after they are
+// returned.
+func SplitVerified(n, k byte, secret []byte) (map[byte][]byte, error) {
+	return splitVerified(n, k, secret, rand.Reader, split)
+}
+
+// split the secret with the given function and verify the result, using the
+// given source of randomness both for the polynomials and to choose the subset
+// to verify
+func splitVerified(n, k byte, secret []byte, rand io.Reader,
+	split func(n, k byte, secret []byte, rand io.Reader) (map[byte][]byte, error),
+) (map[byte][]byte, error) {
+	shares, err := split(n, k, secret, rand)
+	if err != nil {
+		return nil, err
+	}
+
+	subset, err := ShareSet(shares).RandomSubset(k, rand)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok, recovered := VerifyRecovers(subset, secret); !ok {
+		for i := range recovered {
+			recovered[i] = 0
+		}
+		return nil, ErrIntegrity
+	}
+
+	return shares, nil
+}
+
+// CombineWithResidual combines the K shares with the lowest IDs into the
+// original secret, as with Combine, and checks every other share against the
+// recovered polynomials. Returns the largest residual, the XOR of a share's
+// byte with the value the polynomials predict for it, and the IDs of the
+// shares which disagree, in ascending order. A residual of zero means every
+// share is consistent. Returns an error if K is invalid.
+//
+// The check assumes the K lowest-ID shares are correct: if one of them is
+// corrupt, every other share is likely to be reported instead. With K or fewer
+// shares there is nothing to check, and the residual is always zero. As with
+// Combine, shares of differing lengths are compared only as far as the
+// shortest share.
+func CombineWithResidual(shares map[byte][]byte, k byte) (secret []byte, maxResidual byte, badIDs []byte, err error) {
+	if err := checkThreshold(k); err != nil {
+		return nil, 0, nil, err
+	}
+
+	points := sharePoints(shares)
+	if len(points) <= int(k) {
+		return Combine(shares), 0, nil, nil
+	}
+
+	l := -1
+	for _, y := range shares {
+		if l < 0 || len(y) < l {
+			l = len(y)
+		}
+	}
+
+	basis, extra := points[:k], points[k:]
+	secret = make([]byte, l)
+	bad := make(map[byte]bool)
+	for i := range secret {
+		for j := range basis {
+			basis[j].y = shares[basis[j].x][i]
+		}
+		secret[i] = interpolate(basis, 0)
+
+		for _, p := range extra {
+			if r := interpolate(basis, p.x) ^ shares[p.x][i]; r != 0 {
+				maxResidual = max(maxResidual, r)
+				bad[p.x] = true
+			}
+		}
+	}
+
+	for _, p := range extra {
+		if bad[p.x] {
+			badIDs = append(badIDs, p.x)
+		}
+	}
+	return secret, maxResidual, badIDs, nil
+}
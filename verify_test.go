@@ -0,0 +1,244 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestQuickVerify(t *testing.T) {
+	shares, err := Split(5, 3, []byte("well hello there!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := QuickVerify(shares, 3, 8, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ok {
+		t.Error("Consistent shares failed verification")
+	}
+}
+
+func TestQuickVerifyCorrupt(t *testing.T) {
+	for _, id := range []byte{1, 5} {
+		shares, err := Split(5, 3, bytes.Repeat([]byte("well hello there!"), 10))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// corrupt every other byte of a basis share or an extra share
+		for i := 0; i < len(shares[id]); i += 2 {
+			shares[id][i] ^= 0x5a
+		}
+
+		// the chance of missing this is 2^-32
+		if ok, err := QuickVerify(shares, 3, 32, rand.Reader); ok || err != nil {
+			t.Errorf("Corrupt share %v passed verification", id)
+		}
+	}
+}
+
+func TestQuickVerifyTooFewShares(t *testing.T) {
+	shares := map[byte][]byte{1: {1}, 2: {2}}
+
+	if ok, err := QuickVerify(shares, 2, 8, rand.Reader); !ok || err != nil {
+		t.Error("Exactly K shares failed verification")
+	}
+}
+
+func TestQuickVerifyMismatchedLength(t *testing.T) {
+	shares := map[byte][]byte{1: {1}, 2: {2}, 3: {3, 4}}
+
+	if ok, err := QuickVerify(shares, 2, 8, rand.Reader); ok || err != nil {
+		t.Error("Mismatched shares passed verification")
+	}
+}
+
+func TestQuickVerifyEOF(t *testing.T) {
+	shares, err := Split(3, 2, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := QuickVerify(shares, 2, 8, bytes.NewReader(nil)); err != io.EOF {
+		t.Errorf("Was %v, but expected %v", err, io.EOF)
+	}
+}
+
+func TestQuickVerifyInvalidThreshold(t *testing.T) {
+	shares := map[byte][]byte{1: {1}, 2: {2}, 3: {3}}
+
+	if _, err := QuickVerify(shares, 0, 8, rand.Reader); err != ErrZeroThreshold {
+		t.Errorf("Was %v, but expected %v", err, ErrZeroThreshold)
+	}
+}
+
+func TestVerifyRecovers(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, recovered := VerifyRecovers(subset, secret)
+	if !ok {
+		t.Error("Valid shares failed verification")
+	}
+
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("Was %v, but expected %v", recovered, secret)
+	}
+
+	subset[2][4] ^= 1
+
+	if ok, _ := VerifyRecovers(subset, secret); ok {
+		t.Error("Corrupt shares passed verification")
+	}
+}
+
+func TestSplitVerified(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := SplitVerified(5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, want := len(shares), 5; v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := Combine(subset); !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+}
+
+func TestSplitVerifiedDeterministic(t *testing.T) {
+	secret := []byte("well hello there!")
+	entropy := make([]byte, 1024)
+	for i := range entropy {
+		entropy[i] = byte(i*13 + 1)
+	}
+
+	a, err := splitVerified(5, 3, secret, bytes.NewReader(entropy), split)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := splitVerified(5, 3, secret, bytes.NewReader(entropy), split)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for x, y := range a {
+		if v := b[x]; !bytes.Equal(v, y) {
+			t.Errorf("Share %v was %v, but expected %v", x, v, y)
+		}
+	}
+}
+
+func TestSplitVerifiedReadError(t *testing.T) {
+	// enough randomness for the polynomials, but not to choose a subset
+	entropy := make([]byte, 2*len("hi"))
+	for i := range entropy {
+		entropy[i] = 1
+	}
+
+	if _, err := splitVerified(5, 3, []byte("hi"), bytes.NewReader(entropy), split); err == nil {
+		t.Error("Was nil, but expected an error")
+	}
+}
+
+func TestSplitVerifiedCorrupt(t *testing.T) {
+	// a faulty split, with one byte of every share flipped, so that no subset
+	// recovers the secret
+	corrupt := func(n, k byte, secret []byte, rand io.Reader) (map[byte][]byte, error) {
+		shares, err := split(n, k, secret, rand)
+		for _, y := range shares {
+			y[3] ^= 0x10
+		}
+		return shares, err
+	}
+
+	_, err := splitVerified(5, 3, []byte("well hello there!"), rand.Reader, corrupt)
+	if !errors.Is(err, ErrIntegrity) {
+		t.Errorf("Was %v, but expected %v", err, ErrIntegrity)
+	}
+}
+
+func TestCombineWithResidual(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, err := Split(6, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, residual, bad, err := CombineWithResidual(shares, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+
+	if residual != 0 || bad != nil {
+		t.Errorf("Was %v and %v, but expected no residual", residual, bad)
+	}
+
+	shares[4][2] ^= 0x10
+	shares[6][9] ^= 0x80
+
+	v, residual, bad, err = CombineWithResidual(shares, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(v, secret) {
+		t.Errorf("Was %v, but expected %v", v, secret)
+	}
+
+	if v, want := residual, byte(0x80); v != want {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+
+	if v, want := bad, []byte{4, 6}; !bytes.Equal(v, want) {
+		t.Errorf("Was %v, but expected %v", v, want)
+	}
+}
+
+func TestCombineWithResidualTooFewShares(t *testing.T) {
+	shares, err := Split(3, 3, []byte("well hello there!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, residual, bad, err := CombineWithResidual(shares, 3); residual != 0 || bad != nil || err != nil {
+		t.Errorf("Was %v and %v (%v), but expected no residual", residual, bad, err)
+	}
+}
+
+func TestCombineWithResidualInvalidThreshold(t *testing.T) {
+	shares := map[byte][]byte{1: {1}, 2: {2}, 3: {3}}
+
+	if _, _, _, err := CombineWithResidual(shares, 0); err != ErrZeroThreshold {
+		t.Errorf("Was %v, but expected %v", err, ErrZeroThreshold)
+	}
+}
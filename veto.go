@@ -0,0 +1,50 @@
+package sss
+
+import (
+	"io"
+)
+
+// SplitWithVeto splits the given secret into N shares of which K are required
+// to recover the secret, plus a veto share which is also required.
+//
+// The secret is first masked by XORing it with a random pad of the same
+// length, which becomes the veto share, and the masked secret is then split as
+// by Split. Any K of the regular shares recover only the masked secret, which
+// reveals nothing about the secret without the veto share; the veto share
+// alone is random and reveals nothing either. Recovery therefore requires the
+// veto holder and K other parties.
+func SplitWithVeto(n, k byte, secret []byte, rand io.Reader) (map[byte][]byte, []byte, error) {
+	veto := make([]byte, len(secret))
+	if _, err := io.ReadFull(rand, veto); err != nil {
+		return nil, nil, err
+	}
+
+	masked := make([]byte, len(secret))
+	for i := range masked {
+		masked[i] = secret[i] ^ veto[i]
+	}
+
+	shares, err := split(n, k, masked, rand)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return shares, veto, nil
+}
+
+// CombineWithVeto combines the given shares and veto share, as returned by
+// SplitWithVeto, into the original secret.
+//
+// N.B.: As with Combine, there is no way to know whether the returned value
+// is, in fact, the original secret.
+func CombineWithVeto(shares map[byte][]byte, veto []byte) ([]byte, error) {
+	secret := Combine(shares)
+	if len(secret) != len(veto) {
+		return nil, ErrInvalidLength
+	}
+
+	for i := range secret {
+		secret[i] ^= veto[i]
+	}
+	return secret, nil
+}
@@ -0,0 +1,65 @@
+package sss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestSplitWithVeto(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, veto, err := SplitWithVeto(5, 3, secret, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subset, err := ShareSet(shares).Subset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := CombineWithVeto(subset, veto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Errorf("Was %v, but expected %v", actual, secret)
+	}
+}
+
+func TestSplitWithVetoWithoutVeto(t *testing.T) {
+	secret := []byte("well hello there!")
+
+	shares, _, err := SplitWithVeto(5, 3, secret, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// even all the regular shares only recover the masked secret
+	if v := Combine(shares); bytes.Equal(v, secret) {
+		t.Error("Recovered the secret without the veto share")
+	}
+
+	// and the wrong veto share recovers something else
+	actual, err := CombineWithVeto(shares, make([]byte, len(secret)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(actual, secret) {
+		t.Error("Recovered the secret without the veto share")
+	}
+}
+
+func TestCombineWithVetoMismatchedLength(t *testing.T) {
+	shares, veto, err := SplitWithVeto(3, 2, []byte("secret"), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CombineWithVeto(shares, veto[1:]); err != ErrInvalidLength {
+		t.Errorf("Was %v, but expected %v", err, ErrInvalidLength)
+	}
+}